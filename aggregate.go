@@ -18,6 +18,10 @@ const (
 	SUM   AggregateFn = "SUM"
 )
 
+// Aggregate 聚合函数
+// 需要实现 Expression 接口，因为聚合函数可以作为 Predicate 的 left，用在 HAVING 子句中
+// Go中的使用：Having(Avg("Age").GT(30))
+// SQL中的使用：HAVING AVG(`age`) > 30
 type Aggregate struct {
 	// fieldName Go中结构体的字段名
 	fieldName string
@@ -25,6 +29,10 @@ type Aggregate struct {
 	fn AggregateFn
 	// alias 列别名
 	alias string
+	// all 为 true 的时候聚合所有的行，也就是 COUNT(*)
+	all bool
+	// distinct 为 true 的时候只聚合不重复的值，比如 COUNT(DISTINCT `age`)
+	distinct bool
 }
 
 // Avg 平均数
@@ -59,6 +67,14 @@ func Count(fieldName string) Aggregate {
 	}
 }
 
+// CountAll 所有数据的个数，也就是 COUNT(*)
+func CountAll() Aggregate {
+	return Aggregate{
+		fn:  COUNT,
+		all: true,
+	}
+}
+
 // Sum 数据和
 func Sum(fieldName string) Aggregate {
 	return Aggregate{
@@ -76,3 +92,53 @@ func (a Aggregate) As(alias string) Aggregate {
 	a.alias = alias
 	return a
 }
+
+// Distinct 只聚合不重复的值
+// Go中的使用：Count("Age").Distinct()
+// SQL中的使用：COUNT(DISTINCT `age`)
+func (a Aggregate) Distinct() Aggregate {
+	a.distinct = true
+	return a
+}
+
+// expr 标记位
+func (a Aggregate) expr() {}
+
+// selectable 标记位
+func (a Aggregate) selectable() {}
+
+func (a Aggregate) EQ(val any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    EQType,
+		right: exprOf(val),
+	}
+}
+func (a Aggregate) GT(val any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    GTType,
+		right: exprOf(val),
+	}
+}
+func (a Aggregate) GTE(val any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    GTEType,
+		right: exprOf(val),
+	}
+}
+func (a Aggregate) LT(val any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    LTType,
+		right: exprOf(val),
+	}
+}
+func (a Aggregate) LTE(val any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    LTEType,
+		right: exprOf(val),
+	}
+}
@@ -0,0 +1,16 @@
+package orm_framework
+
+// Assignment 赋值，保存 SET 子句中的一个赋值
+// Go中的使用：Assign("Age", 18)、Assign("Stock", F("Stock").Sub(1))
+// SQL中的使用：`age` = ?、`stock` = `stock` - ?
+type Assignment struct {
+	// fieldName Go中的字段名
+	fieldName string
+	// val 需要设置的值，可以是普通的值，也可以是表达式
+	val any
+}
+
+// Assign 初始化一个赋值
+func Assign(fieldName string, val any) Assignment {
+	return Assignment{fieldName: fieldName, val: val}
+}
@@ -1,7 +1,9 @@
 package orm_framework
 
 import (
+	"github.com/borntodie-new/orm-framework/internal/errs"
 	"github.com/borntodie-new/orm-framework/model"
+	"reflect"
 	"strings"
 )
 
@@ -14,11 +16,474 @@ type builder struct {
 	args []any
 	// model 表模型
 	model *model.Model
+	// dialect 方言，所有的引号和占位符都要经过方言处理
+	dialect Dialect
+	// manager model 管理器，JOIN 查询的时候需要解析其他表的模型
+	manager *model.Manager
+	// qualifier 不为空的时候，没有指定表的列都会带上这个表名，比如 `user`.`name`
+	// upsert 的更新部分中 excluded 也是一张表，不带表名的列在 PostgreSQL 中是有歧义的
+	qualifier string
 }
 
-func newBuilder() *builder {
+// quote 构建带引号的标识符，比如表名、列名、别名
+func (b *builder) quote(name string) {
+	b.sb.WriteByte(b.dialect.Quoter())
+	b.sb.WriteString(name)
+	b.sb.WriteByte(b.dialect.Quoter())
+}
+
+// addArgs 添加SQL参数，同时构建对应的占位符
+// 注意：val 为 nil 的时候也要添加，它对应的是 SQL 中的 NULL，否则占位符和参数就对不上了
+func (b *builder) addArgs(val any) {
+	b.args = append(b.args, val)
+	b.sb.WriteString(b.dialect.Placeholder(len(b.args)))
+}
+
+// nullable 写入列的值，nil 指针转换成 nil，这样数据库中存的就是 NULL
+// 虽然大部分驱动也会这么处理，但是带类型的 nil 指针在 SQLInfo.Args 中不直观，也不是所有的驱动都支持
+func nullable(val any) any {
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil
+	}
+	return val
+}
+
+// buildValueList 构建 IN 的参数列表 (?, ?, ?)
+func (b *builder) buildValueList(list valueList) error {
+	if len(list.vals) <= 0 {
+		return errs.ErrNoInValues
+	}
+	b.sb.WriteByte('(')
+	for idx, val := range list.vals {
+		if idx > 0 {
+			b.sb.WriteString(", ")
+		}
+		b.addArgs(val)
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildBetweenValue 构建 BETWEEN 的参数 ? AND ?
+func (b *builder) buildBetweenValue(val betweenValue) {
+	b.addArgs(val.start)
+	b.sb.WriteString(" AND ")
+	b.addArgs(val.end)
+}
+
+// buildWhere 构建 WHERE 子句，多个条件之间用 AND 连接
+func (b *builder) buildWhere(where []Predicate) error {
+	if len(where) <= 0 {
+		return nil
+	}
+	b.sb.WriteString(" WHERE ")
+	return b.buildPredicates(where)
+}
+
+// buildPredicates 构建多个条件，多个条件之间用 AND 连接
+// WHERE 和 HAVING 子句都会用到
+func (b *builder) buildPredicates(ps []Predicate) error {
+	p := ps[0]
+	for i := 1; i < len(ps); i++ {
+		p = p.AND(ps[i])
+	}
+	return b.buildExpression(p)
+}
+
+// buildExpression 构建表达式，所有语句的 WHERE、HAVING 都是用这个方法构建的
+// 括号的规则如下：
+// 1. 比较运算符，比如 `id` = ?，两边总是会加上括号，这样就不会有优先级问题了
+// 2. AND、OR、NOT 的子条件优先级比自己低的时候才加括号，比如 ((`id` = ?) OR (`age` > ?)) AND (`first_name` = ?)
+func (b *builder) buildExpression(exp Expression) error {
+	p, ok := exp.(Predicate)
+	if !ok {
+		return b.buildOperand(exp)
+	}
+	switch p.op {
+	case NOTType:
+		b.sb.WriteString(p.op.String())
+		return b.buildSubPredicate(p.right, p.precedence())
+	case EXISTSType, NOTEXISTSType:
+		// EXISTS 后面的子查询本身就有括号，所以不需要再加了
+		b.sb.WriteString(p.op.String())
+		return b.buildOperand(p.right)
+	case ANDType, ORType:
+		if err := b.buildSubPredicate(p.left, p.precedence()); err != nil {
+			return err
+		}
+		b.sb.WriteString(p.op.String())
+		// 右边优先级相同的时候不需要加括号，因为 AND 和 OR 都满足结合律
+		return b.buildSubPredicate(p.right, p.precedence())
+	default:
+		return b.buildComparison(p)
+	}
+}
+
+// buildSubPredicate 构建 AND、OR、NOT 的子条件
+// 子条件的优先级比 parent 低的时候需要加括号
+func (b *builder) buildSubPredicate(exp Expression, parent int) error {
+	p, ok := exp.(Predicate)
+	if !ok || p.precedence() >= parent {
+		return b.buildExpression(exp)
+	}
+	b.sb.WriteByte('(')
+	if err := b.buildExpression(p); err != nil {
+		return err
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildComparison 构建比较运算，两边总是加上括号
+// 例如：(`id` = ?)、(`id` IN (?, ?))、(`age` BETWEEN ? AND ?)、(`name` IS NULL)
+func (b *builder) buildComparison(p Predicate) error {
+	b.sb.WriteByte('(')
+	if err := b.buildOperand(p.left); err != nil {
+		return err
+	}
+	b.sb.WriteString(p.op.String())
+	switch typ := p.right.(type) {
+	case nil:
+		// IS NULL 这种操作符是没有右边的
+	case valueList:
+		if err := b.buildValueList(typ); err != nil {
+			return err
+		}
+	case betweenValue:
+		b.buildBetweenValue(typ)
+	default:
+		if err := b.buildOperand(typ); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildAggregate 构建聚合函数，不包括别名
+// 在 SELECT 列表和 HAVING 子句中都会用到，也可以作为算术表达式的一部分
+func (b *builder) buildAggregate(ag Aggregate) error {
+	// COUNT(*) 是没有字段名的
+	if ag.all {
+		b.sb.WriteString(ag.fn.String())
+		b.sb.WriteString("(*)")
+		return nil
+	}
+	if ag.fieldName == "" {
+		return errs.ErrNoFieldName
+	}
+	fd, ok := b.model.FieldsMap[ag.fieldName]
+	if !ok {
+		return errs.NewErrNotSupportUnknownField(ag.fieldName)
+	}
+	// 普通的列不能单独使用 DISTINCT，整个查询去重应该用 SelectSQL.Distinct
+	if ag.distinct && ag.fn == "" {
+		return errs.ErrDistinctWithoutAggregate
+	}
+	// 是否是聚合函数操作
+	if ag.fn != "" {
+		b.sb.WriteString(ag.fn.String())
+		b.sb.WriteByte('(')
+	}
+	if ag.distinct {
+		b.sb.WriteString("DISTINCT ")
+	}
+	// 构建普通的列名
+	b.quote(fd.ColumnName)
+	if ag.fn != "" {
+		b.sb.WriteByte(')')
+	}
+	return nil
+}
+
+// buildOperand 构建操作数，也就是列、值、聚合函数和算术表达式
+// 注意：这里不会在两边加括号，主要用于比较运算的两边和 UPDATE 语句的 SET 子句
+func (b *builder) buildOperand(exp Expression) error {
+	switch typ := exp.(type) {
+	case Field:
+		return b.buildColumn(typ)
+	case Value:
+		b.addArgs(typ.val)
+	case Aggregate:
+		return b.buildAggregate(typ)
+	case MathExpr:
+		return b.buildMathExpr(typ)
+	case Subquery:
+		return b.buildSubquery(typ)
+	case excludedValue:
+		fd, ok := b.model.FieldsMap[typ.fieldName]
+		if !ok {
+			return errs.NewErrNotSupportUnknownField(typ.fieldName)
+		}
+		b.dialect.BuildExcluded(sqlBuilder{b: b}, fd.ColumnName)
+	case Predicate:
+		// 条件作为操作数，比如 F("Flag").EQ(F("Age").GT(18))，按照比较运算的优先级处理
+		return b.buildSubPredicate(typ, Predicate{}.precedence())
+	default:
+		return errs.ErrNotSupportPredicate
+	}
+	return nil
+}
+
+// buildColumn 构建列名
+// Field 属于某个表的时候，用这个表的模型解析字段，并且带上表名或者别名，比如 `u`.`name`
+// 否则用语句本身的模型解析字段
+func (b *builder) buildColumn(f Field) error {
+	m := b.model
+	if f.table != nil {
+		var err error
+		m, err = b.tableModel(f.table)
+		if err != nil {
+			return err
+		}
+	}
+	fd, ok := m.FieldsMap[f.fieldName]
+	if !ok {
+		return errs.NewErrNotSupportUnknownField(f.fieldName)
+	}
+	if f.table != nil {
+		alias := f.table.tableAlias()
+		if tbl, ok := f.table.(Table); ok && alias == "" {
+			alias = tbl.tableName(m)
+		}
+		b.quote(alias)
+		b.sb.WriteByte('.')
+	} else if b.qualifier != "" {
+		b.quote(b.qualifier)
+		b.sb.WriteByte('.')
+	}
+	b.quote(fd.ColumnName)
+	return nil
+}
+
+// buildSelectable 构建 SELECT 列表中的一个元素，包括别名
+func (b *builder) buildSelectable(sel Selectable) error {
+	var alias string
+	switch typ := sel.(type) {
+	case Aggregate:
+		if err := b.buildAggregate(typ); err != nil {
+			return err
+		}
+		alias = typ.alias
+	case Field:
+		if err := b.buildColumn(typ); err != nil {
+			return err
+		}
+		alias = typ.alias
+	case Subquery:
+		if err := b.buildSubquery(typ); err != nil {
+			return err
+		}
+		alias = typ.alias
+	case Window:
+		if err := b.buildWindow(typ); err != nil {
+			return err
+		}
+		alias = typ.alias
+	default:
+		return errs.ErrNotSupportSelectable
+	}
+	// 构建列的别名
+	if alias != "" {
+		b.sb.WriteString(" AS ")
+		b.quote(alias)
+	}
+	return nil
+}
+
+// tableModel 获取表的模型
+func (b *builder) tableModel(tbl TableReference) (*model.Model, error) {
+	switch typ := tbl.(type) {
+	case Table:
+		return b.manager.Get(typ.entity)
+	case Subquery:
+		return b.manager.Get(typ.q.entity())
+	default:
+		return nil, errs.ErrNotSupportTableReference
+	}
+}
+
+// buildTable 构建 FROM 后面的表
+func (b *builder) buildTable(tbl TableReference) error {
+	switch typ := tbl.(type) {
+	case Table:
+		m, err := b.tableModel(typ)
+		if err != nil {
+			return err
+		}
+		b.quote(typ.tableName(m))
+		if typ.alias != "" {
+			b.sb.WriteString(" AS ")
+			b.quote(typ.alias)
+		}
+	case Join:
+		return b.buildJoin(typ)
+	case Subquery:
+		// 派生表必须要有别名
+		if typ.alias == "" {
+			return errs.ErrSubqueryNoAlias
+		}
+		if err := b.buildSubquery(typ); err != nil {
+			return err
+		}
+		b.sb.WriteString(" AS ")
+		b.quote(typ.alias)
+	default:
+		return errs.ErrNotSupportTableReference
+	}
+	return nil
+}
+
+// buildSubquery 构建子查询，不包括别名，比如 (SELECT `id` FROM `order` WHERE (`amount` > ?))
+// 子查询的参数会按照顺序追加到当前语句的参数中
+func (b *builder) buildSubquery(sub Subquery) error {
+	b.sb.WriteByte('(')
+	if err := sub.q.buildSubquery(b); err != nil {
+		return err
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildJoin 构建 JOIN 查询
+// 左边是 JOIN 的时候不需要加括号，因为 JOIN 本身就是从左往右结合的
+// 右边是 JOIN 的时候需要加括号，比如 `a` JOIN (`b` JOIN `c` ON ...) ON ...
+func (b *builder) buildJoin(j Join) error {
+	if err := b.buildTable(j.left); err != nil {
+		return err
+	}
+	b.sb.WriteByte(' ')
+	b.sb.WriteString(j.typ)
+	b.sb.WriteByte(' ')
+	_, ok := j.right.(Join)
+	if ok {
+		b.sb.WriteByte('(')
+	}
+	if err := b.buildTable(j.right); err != nil {
+		return err
+	}
+	if ok {
+		b.sb.WriteByte(')')
+	}
+	if len(j.on) > 0 {
+		b.sb.WriteString(" ON ")
+		return b.buildPredicates(j.on)
+	}
+	if len(j.using) > 0 {
+		return b.buildUsing(j)
+	}
+	return nil
+}
+
+// buildUsing 构建 USING 子句
+// USING 的字段两边的表都有，这里用 JOIN 最左边的表来解析字段名
+func (b *builder) buildUsing(j Join) error {
+	left := j.left
+	for {
+		lj, ok := left.(Join)
+		if !ok {
+			break
+		}
+		left = lj.left
+	}
+	m, err := b.tableModel(left)
+	if err != nil {
+		return err
+	}
+	b.sb.WriteString(" USING (")
+	for idx, fieldName := range j.using {
+		if idx > 0 {
+			b.sb.WriteString(", ")
+		}
+		fd, ok := m.FieldsMap[fieldName]
+		if !ok {
+			return errs.NewErrNotSupportUnknownField(fieldName)
+		}
+		b.quote(fd.ColumnName)
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildAssignments 构建赋值列表，比如 `first_name` = ?, `age` = `age` + ?
+// UPDATE 语句的 SET 子句和 upsert 的更新部分都会用到
+func (b *builder) buildAssignments(assigns []Assignment) error {
+	for idx, assign := range assigns {
+		if idx > 0 {
+			b.sb.WriteString(", ")
+		}
+		fd, ok := b.model.FieldsMap[assign.fieldName]
+		if !ok {
+			return errs.NewErrNotSupportUnknownField(assign.fieldName)
+		}
+		// 设置列名
+		b.quote(fd.ColumnName)
+		b.sb.WriteString(" = ")
+		// 值可以是表达式，比如 Values("Stock", F("Stock").Sub(1))
+		if exp, ok := assign.val.(Expression); ok {
+			if err := b.buildOperand(exp); err != nil {
+				return err
+			}
+			continue
+		}
+		// 设置占位符，同时保存数据
+		b.addArgs(nullable(assign.val))
+	}
+	return nil
+}
+
+// buildOrders 构建排序条件，不包括 ORDER BY 关键字，比如 `age` ASC, `id` DESC
+func (b *builder) buildOrders(orders []Order) error {
+	for idx, ob := range orders {
+		if idx > 0 {
+			b.sb.WriteString(", ")
+		}
+		if err := b.buildColumn(ob.field); err != nil {
+			return err
+		}
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(ob.order)
+	}
+	return nil
+}
+
+// buildMathExpr 构建算术表达式
+// 子表达式的优先级比当前运算符低的时候需要加括号，比如 (`a` + ?) * ?
+// 右边的子表达式优先级相同的时候也要加括号，因为减法和除法不满足结合律，比如 `a` - (`b` - ?)
+func (b *builder) buildMathExpr(exp MathExpr) error {
+	left, ok := exp.left.(MathExpr)
+	wrap := ok && left.op.precedence() < exp.op.precedence()
+	if err := b.buildWrappedOperand(exp.left, wrap); err != nil {
+		return err
+	}
+	b.sb.WriteString(exp.op.String())
+	right, ok := exp.right.(MathExpr)
+	wrap = ok && right.op.precedence() <= exp.op.precedence()
+	return b.buildWrappedOperand(exp.right, wrap)
+}
+
+// buildWrappedOperand 构建操作数，wrap 为 true 的时候在两边加上括号
+func (b *builder) buildWrappedOperand(exp Expression, wrap bool) error {
+	if wrap {
+		b.sb.WriteByte('(')
+	}
+	if err := b.buildOperand(exp); err != nil {
+		return err
+	}
+	if wrap {
+		b.sb.WriteByte(')')
+	}
+	return nil
+}
+
+// newBuilder 创建 SQL 构造器
+// 语句在每次 Build 的时候都会调用这个方法重新创建，这样同一个语句多次 Build 或者执行的时候结果是一样的
+func newBuilder(sess Session) *builder {
 	return &builder{
-		sb:   &strings.Builder{},
-		args: []any{},
+		sb:      &strings.Builder{},
+		args:    []any{},
+		dialect: sess.getCore().dialect,
+		manager: sess.getCore().manager,
 	}
 }
@@ -0,0 +1,274 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+)
+
+type compoundOp string
+
+func (c compoundOp) String() string {
+	return string(c)
+}
+
+const (
+	UNIONType     compoundOp = " UNION "
+	UNIONALLType  compoundOp = " UNION ALL "
+	INTERSECTType compoundOp = " INTERSECT "
+	EXCEPTType    compoundOp = " EXCEPT "
+)
+
+// compoundPart 组合查询中的一个分支，以及它和前面的分支之间的操作符
+type compoundPart[T any] struct {
+	op compoundOp
+	q  *SelectSQL[T]
+}
+
+// CompoundSQL 组合查询，也就是 UNION、UNION ALL、INTERSECT、EXCEPT
+// 1. 需要实现 Builder 接口，用于构建SQL语句和保存SQL的参数
+// 2. 需要实现 Querier 接口，用于接收SQL返回的结果集
+// 3. 需要实现 QueryBuilder 接口，这样组合查询也可以作为子查询使用
+// Go中的使用：NewSelectSQL[User](db, nil).Where(F("Age").LT(18)).Union(NewSelectSQL[User](db, nil).Where(F("Age").GT(60))).OrderBy(Asc("Id"))
+// SQL中的使用：SELECT * FROM `user` WHERE (`age` < ?) UNION SELECT * FROM `user` WHERE (`age` > ?) ORDER BY `id` ASC;
+// ⚠️：分支两边是不加括号的，因为 SQLite 不支持，所以分支中不能有 ORDER BY、LIMIT 和 OFFSET，需要的话设置在组合查询上
+// 多个操作符之间按照从左往右的顺序计算
+type CompoundSQL[T any] struct {
+	// sess 执行语句的会话，可以是 DB 也可以是 Tx
+	sess Session
+	// parts 组合查询的所有分支，第一个分支的 op 是空的
+	parts []compoundPart[T]
+	// orderBy 对整个组合查询的结果排序
+	orderBy []Order
+	// limit 最多返回多少条数据，0 表示不限制
+	limit int
+	// offset 跳过多少条数据，0 表示不跳过
+	offset int
+
+	*builder
+
+	// valuer 映射字段接口
+	valuer valuer.FactoryValuer
+}
+
+var (
+	_ Querier[any] = &CompoundSQL[any]{}
+	_ QueryBuilder = &CompoundSQL[any]{}
+)
+
+func (s *SelectSQL[T]) Union(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return newCompoundSQL(s).compound(UNIONType, others)
+}
+
+func (s *SelectSQL[T]) UnionAll(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return newCompoundSQL(s).compound(UNIONALLType, others)
+}
+
+func (s *SelectSQL[T]) Intersect(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return newCompoundSQL(s).compound(INTERSECTType, others)
+}
+
+func (s *SelectSQL[T]) Except(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return newCompoundSQL(s).compound(EXCEPTType, others)
+}
+
+func (c *CompoundSQL[T]) Union(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return c.compound(UNIONType, others)
+}
+
+func (c *CompoundSQL[T]) UnionAll(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return c.compound(UNIONALLType, others)
+}
+
+func (c *CompoundSQL[T]) Intersect(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return c.compound(INTERSECTType, others)
+}
+
+func (c *CompoundSQL[T]) Except(others ...*SelectSQL[T]) *CompoundSQL[T] {
+	return c.compound(EXCEPTType, others)
+}
+
+func (c *CompoundSQL[T]) compound(op compoundOp, others []*SelectSQL[T]) *CompoundSQL[T] {
+	for _, q := range others {
+		c.parts = append(c.parts, compoundPart[T]{op: op, q: q})
+	}
+	return c
+}
+
+// OrderBy 对整个组合查询的结果排序，字段名用 T 来解析
+func (c *CompoundSQL[T]) OrderBy(orders ...Order) *CompoundSQL[T] {
+	c.orderBy = append(c.orderBy, orders...)
+	return c
+}
+
+// Limit 设置整个组合查询最多返回多少条数据
+func (c *CompoundSQL[T]) Limit(limit int) *CompoundSQL[T] {
+	c.limit = limit
+	return c
+}
+
+// Offset 设置整个组合查询跳过多少条数据
+func (c *CompoundSQL[T]) Offset(offset int) *CompoundSQL[T] {
+	c.offset = offset
+	return c
+}
+
+// QueryWithContext 查询多条数据
+func (c *CompoundSQL[T]) QueryWithContext(ctx context.Context) ([]*T, error) {
+	sqlInfo, err := c.Build()
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	tps := make([]*T, 0)
+	for res.Next() {
+		tp := new(T)
+		val := c.valuer(c.model, tp)
+		err = val.SetField(res)
+		if err != nil {
+			return nil, err
+		}
+		tps = append(tps, tp)
+	}
+	return tps, nil
+}
+
+// QueryRawWithContext 查询单条数据
+func (c *CompoundSQL[T]) QueryRawWithContext(ctx context.Context) (*T, error) {
+	sqlInfo, err := c.Build()
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	if !res.Next() {
+		return nil, errs.ErrNoRows
+	}
+	tp := new(T)
+	val := c.valuer(c.model, tp)
+	err = val.SetField(res)
+	return tp, err
+}
+
+func (c *CompoundSQL[T]) Build() (*SQLInfo, error) {
+	c.builder = newBuilder(c.sess)
+	if err := c.build(); err != nil {
+		return nil, err
+	}
+	c.sb.WriteByte(';')
+	return &SQLInfo{SQL: c.sb.String(), Args: c.args}, nil
+}
+
+// buildSubquery 把组合查询作为子查询构建到 parent 中
+func (c *CompoundSQL[T]) buildSubquery(parent *builder) error {
+	origin := c.builder
+	defer func() {
+		c.builder = origin
+	}()
+	c.builder = &builder{
+		sb:      parent.sb,
+		args:    parent.args,
+		dialect: parent.dialect,
+		manager: parent.manager,
+	}
+	err := c.build()
+	parent.args = c.args
+	return err
+}
+
+// entity 返回 T 的一级指针，用于获取子查询的表模型
+func (c *CompoundSQL[T]) entity() any {
+	return new(T)
+}
+
+// session 返回执行语句的会话
+func (c *CompoundSQL[T]) session() Session {
+	return c.sess
+}
+
+// As 把组合查询作为子查询使用
+func (c *CompoundSQL[T]) As(alias string) Subquery {
+	return Subquery{q: c, alias: alias}
+}
+
+// build 构建组合查询，不包括结尾的分号
+func (c *CompoundSQL[T]) build() error {
+	var err error
+	c.model, err = c.sess.getCore().manager.Get(new(T))
+	if err != nil {
+		return err
+	}
+	ctes, err := c.branchCTEs()
+	if err != nil {
+		return err
+	}
+	if err = c.buildWith(ctes); err != nil {
+		return err
+	}
+	for _, part := range c.parts {
+		// 分支没有括号，ORDER BY 和 LIMIT 会被当成整个组合查询的，所以直接报错
+		if len(part.q.orderBy) > 0 || part.q.limit > 0 || part.q.offset > 0 {
+			return errs.ErrOrderByOrLimitInCompoundBranch
+		}
+		c.sb.WriteString(part.op.String())
+		if err = c.buildBranch(part.q); err != nil {
+			return err
+		}
+	}
+	if len(c.orderBy) > 0 {
+		c.sb.WriteString(" ORDER BY ")
+		if err = c.buildOrders(c.orderBy); err != nil {
+			return err
+		}
+	}
+	c.dialect.BuildLimit(sqlBuilder{b: c.builder}, c.limit, c.offset)
+	return nil
+}
+
+// branchCTEs 收集所有分支中的 CTE
+// WITH 只能出现在整个语句的开头，所以分支中的 CTE 要统一提到组合查询的最前面
+// 多个分支使用同一个 CTE 的时候只定义一次，名字相同但是定义不同的 CTE 会报错
+func (c *CompoundSQL[T]) branchCTEs() ([]cte, error) {
+	var ctes []cte
+	defined := make(map[string]cte)
+	for _, part := range c.parts {
+		for _, item := range part.q.ctes {
+			if prev, ok := defined[item.name]; ok {
+				if prev != item {
+					return nil, errs.NewErrDuplicateCTE(item.name)
+				}
+				continue
+			}
+			defined[item.name] = item
+			ctes = append(ctes, item)
+		}
+	}
+	return ctes, nil
+}
+
+// buildBranch 构建一个分支，分支中的 CTE 已经提到组合查询的最前面了，这里不再构建
+func (c *CompoundSQL[T]) buildBranch(q *SelectSQL[T]) error {
+	ctes := q.ctes
+	q.ctes = nil
+	defer func() {
+		q.ctes = ctes
+	}()
+	return q.buildSubquery(c.builder)
+}
+
+// newCompoundSQL 用第一个分支初始化组合查询，执行的会话和映射字段接口都用第一个分支的
+func newCompoundSQL[T any](first *SelectSQL[T]) *CompoundSQL[T] {
+	return &CompoundSQL[T]{
+		sess:    first.sess,
+		parts:   []compoundPart[T]{{q: first}},
+		builder: newBuilder(first.sess),
+		valuer:  first.valuer,
+	}
+}
@@ -0,0 +1,173 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCompoundSQL_Build(t *testing.T) {
+	db := memoryDB(t)
+	young := func() *SelectSQL[TestModel] {
+		return NewSelectSQL[TestModel](db, nil).Where(F("Age").LT(18))
+	}
+	old := func() *SelectSQL[TestModel] {
+		return NewSelectSQL[TestModel](db, nil).Where(F("Age").GT(60))
+	}
+	testCases := []struct {
+		name    string
+		c       *CompoundSQL[TestModel]
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test union",
+			c:    young().Union(old()),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` < ?) UNION SELECT * FROM `test_model` WHERE (`age` > ?);",
+				Args: []any{18, 60},
+			},
+		},
+		{
+			name: "test union all with order by and limit",
+			c:    young().UnionAll(old()).OrderBy(Desc("Age")).Limit(10).Offset(5),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` < ?) UNION ALL SELECT * FROM `test_model` WHERE (`age` > ?) ORDER BY `age` DESC LIMIT ? OFFSET ?;",
+				Args: []any{18, 60, 10, 5},
+			},
+		},
+		{
+			name: "test mixed operators",
+			c: young().Union(old(), NewSelectSQL[TestModel](db, nil).Where(F("Id").EQ(1))).
+				Except(NewSelectSQL[TestModel](db, nil).Where(F("FirstName").EQ("Neo"))).
+				Intersect(NewSelectSQL[TestModel](db, nil).Where(F("Id").LT(100))),
+			wantRes: &SQLInfo{
+				SQL: "SELECT * FROM `test_model` WHERE (`age` < ?) UNION SELECT * FROM `test_model` WHERE (`age` > ?) " +
+					"UNION SELECT * FROM `test_model` WHERE (`id` = ?) EXCEPT SELECT * FROM `test_model` WHERE (`first_name` = ?) " +
+					"INTERSECT SELECT * FROM `test_model` WHERE (`id` < ?);",
+				Args: []any{18, 60, 1, "Neo", 100},
+			},
+		},
+		{
+			name: "test cte in branches",
+			c: NewSelectSQL[TestModel](db, nil).With("y", young()).From(CTE[TestModel]("y")).
+				Union(NewSelectSQL[TestModel](db, nil).With("o", old()).From(CTE[TestModel]("o")).Where(F("Id").GT(1))),
+			wantRes: &SQLInfo{
+				SQL: "WITH `y` AS (SELECT * FROM `test_model` WHERE (`age` < ?)), `o` AS (SELECT * FROM `test_model` WHERE (`age` > ?)) " +
+					"SELECT * FROM `y` UNION SELECT * FROM `o` WHERE (`id` > ?);",
+				Args: []any{18, 60, 1},
+			},
+		},
+		{
+			name: "test same cte in branches",
+			c: func() *CompoundSQL[TestModel] {
+				y := young()
+				return NewSelectSQL[TestModel](db, nil).With("y", y).From(CTE[TestModel]("y")).Where(F("Id").LT(10)).
+					Union(NewSelectSQL[TestModel](db, nil).With("y", y).From(CTE[TestModel]("y")).Where(F("Id").GT(20)))
+			}(),
+			wantRes: &SQLInfo{
+				SQL:  "WITH `y` AS (SELECT * FROM `test_model` WHERE (`age` < ?)) SELECT * FROM `y` WHERE (`id` < ?) UNION SELECT * FROM `y` WHERE (`id` > ?);",
+				Args: []any{18, 10, 20},
+			},
+		},
+		{
+			name: "test duplicate cte name in branches",
+			c: NewSelectSQL[TestModel](db, nil).With("c", young()).From(CTE[TestModel]("c")).
+				Union(NewSelectSQL[TestModel](db, nil).With("c", old()).From(CTE[TestModel]("c"))),
+			wantErr: errs.NewErrDuplicateCTE("c"),
+		},
+		{
+			name:    "test order by in branch",
+			c:       young().Union(old().OrderBy(Asc("Id"))),
+			wantErr: errs.ErrOrderByOrLimitInCompoundBranch,
+		},
+		{
+			name:    "test invalid order by field",
+			c:       young().Union(old()).OrderBy(Asc("Invalid")),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.c.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+
+	// 组合查询作为子查询
+	res, err := NewSelectSQL[TestOrder](db, nil).Where(F("Amount").GT(100), F("UserId").InQuery(young().Fields(F("Id")).Union(old().Fields(F("Id"))))).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &SQLInfo{
+		SQL:  "SELECT * FROM `test_order` WHERE (`amount` > ?) AND (`user_id` IN (SELECT `id` FROM `test_model` WHERE (`age` < ?) UNION SELECT `id` FROM `test_model` WHERE (`age` > ?)));",
+		Args: []any{100, 18, 60},
+	}, res)
+}
+
+func TestCompoundSQL_QueryWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db, err := Open("sqlite3", "file:compound.db?cache=shared&mode=memory", DBWithDialect(SQLite3))
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_model` (`id`, `first_name`, `age`) VALUES (1, 'Tom', 10), (2, 'Neo', 30), (3, 'Jason', 70), (4, 'Alice', 15);")
+	assert.NoError(t, err)
+
+	s := func() *SelectSQL[TestModel] {
+		return NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(F("Id"), F("FirstName"))
+	}
+	testCases := []struct {
+		name    string
+		c       Querier[TestModel]
+		wantRes []*TestModel
+	}{
+		{
+			name: "test union",
+			c:    s().Where(F("Age").LT(18)).Union(s().Where(F("Age").GT(60)), s().Where(F("Id").EQ(1))).OrderBy(Desc("Id")),
+			wantRes: []*TestModel{
+				{Id: 4, FirstName: "Alice"},
+				{Id: 3, FirstName: "Jason"},
+				{Id: 1, FirstName: "Tom"},
+			},
+		},
+		{
+			name: "test union all",
+			c:    s().Where(F("Age").LT(18)).UnionAll(s().Where(F("Id").EQ(1))).OrderBy(Asc("Id")).Limit(2),
+			wantRes: []*TestModel{
+				{Id: 1, FirstName: "Tom"},
+				{Id: 1, FirstName: "Tom"},
+			},
+		},
+		{
+			name:    "test intersect",
+			c:       s().Where(F("Age").LT(18)).Intersect(s().Where(F("Id").GT(1))),
+			wantRes: []*TestModel{{Id: 4, FirstName: "Alice"}},
+		},
+		{
+			name:    "test except",
+			c:       s().Except(s().Where(F("Age").LT(60))),
+			wantRes: []*TestModel{{Id: 3, FirstName: "Jason"}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.c.QueryWithContext(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+
+	res, err := s().Where(F("Age").LT(18)).Union(s().Where(F("Age").GT(60))).OrderBy(Asc("Id")).Offset(1).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 3, FirstName: "Jason"}, res)
+	_, err = s().Where(F("Age").GT(100)).Union(s().Where(F("Age").LT(0))).QueryRawWithContext(ctx)
+	assert.Equal(t, errs.ErrNoRows, err)
+}
@@ -0,0 +1,55 @@
+package orm_framework
+
+// cte 公用表表达式，也就是 WITH 子句中的一项
+// Go中的使用：With("big_order", NewSelectSQL[Order](db, nil).Where(F("Amount").GT(100)))
+// SQL中的使用：WITH `big_order` AS (SELECT * FROM `order` WHERE (`amount` > ?))
+type cte struct {
+	// name CTE 的名字，外层查询通过 CTE[T](name) 引用
+	name string
+	// q CTE 的查询语句，递归 CTE 的时候是锚点部分
+	q QueryBuilder
+	// recursive 递归部分，为空就是普通的 CTE
+	// 递归部分和锚点部分之间用 UNION ALL 连接
+	recursive QueryBuilder
+}
+
+// CTE 引用 WITH 子句中定义的 CTE，T 用于解析字段名
+// Go中的使用：From(CTE[Employee]("tree"))
+// SQL中的使用：FROM `tree`
+func CTE[T any](name string) Table {
+	return Table{entity: new(T), name: name}
+}
+
+// buildWith 构建 WITH 子句，只要有一个 CTE 是递归的，就要使用 WITH RECURSIVE
+// 例如：WITH RECURSIVE `tree` AS (SELECT ... UNION ALL SELECT ...)
+func (b *builder) buildWith(ctes []cte) error {
+	if len(ctes) <= 0 {
+		return nil
+	}
+	b.sb.WriteString("WITH ")
+	for _, c := range ctes {
+		if c.recursive != nil {
+			b.sb.WriteString("RECURSIVE ")
+			break
+		}
+	}
+	for idx, c := range ctes {
+		if idx > 0 {
+			b.sb.WriteString(", ")
+		}
+		b.quote(c.name)
+		b.sb.WriteString(" AS (")
+		if err := c.q.buildSubquery(b); err != nil {
+			return err
+		}
+		if c.recursive != nil {
+			b.sb.WriteString(" UNION ALL ")
+			if err := c.recursive.buildSubquery(b); err != nil {
+				return err
+			}
+		}
+		b.sb.WriteByte(')')
+	}
+	b.sb.WriteByte(' ')
+	return nil
+}
@@ -1,29 +1,134 @@
 package orm_framework
 
 import (
+	"context"
 	"database/sql"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
 	"github.com/borntodie-new/orm-framework/model"
 )
 
+var _ Session = &DB{}
+
 type DB struct {
+	core
+	// naming DBWithNamingStrategy 指定的命名策略，所有的配置执行完之后再设置到 manager 上
+	// 这样和 DBWithManager 一起使用的时候，结果和配置的顺序无关
+	naming model.NamingStrategy
 	// db 真实客SQL做交互的数据库连接对象
 	db *sql.DB
-	// manager model 管理器
-	manager *model.Manager
 }
 
+// DBOption DB 的可选配置
+type DBOption func(db *DB)
+
 // Open 创建自定义的 DB 实例对象
-func Open(driver string, dataSourceName string) (*DB, error) {
+// 会根据驱动名称选择数据库方言，DBWithDialect 指定的方言优先
+func Open(driver string, dataSourceName string, opts ...DBOption) (*DB, error) {
 	db, err := sql.Open(driver, dataSourceName)
 	if err != nil {
 		return nil, err
 	}
-	return OpenDB(db)
+	if dialect, ok := driverDialects[driver]; ok {
+		opts = append([]DBOption{DBWithDialect(dialect)}, opts...)
+	}
+	return OpenDB(db, opts...)
+}
+
+// driverDialects 常用驱动对应的数据库方言
+// 方言不对的时候不仅 SQL 的语法有问题，批量插入之后回填的自增 ID 也会出错
+var driverDialects = map[string]Dialect{
+	"mysql":    MySQL,
+	"sqlite3":  SQLite3,
+	"sqlite":   SQLite3,
+	"postgres": PostgreSQL,
+	"pgx":      PostgreSQL,
 }
 
 // OpenDB 创建自定义的 DB 实例对象
 // 疑问：为什么已经有了 Open 方法，还需要提供这个方法
 // 为了扩展性，这也是 Go 内置的 sql 的设计传统
-func OpenDB(db *sql.DB) (*DB, error) {
-	return &DB{db: db, manager: &model.Manager{}}, nil
+func OpenDB(db *sql.DB, opts ...DBOption) (*DB, error) {
+	res := &DB{
+		core: core{
+			manager: model.NewManager(),
+			// 默认使用 MySQL 方言
+			dialect: MySQL,
+			// 默认使用 unsafe 的实现，性能更好
+			valuer: valuer.NewUnsafeValuer,
+		},
+		db: db,
+	}
+	for _, opt := range opts {
+		opt(res)
+	}
+	if res.naming != nil {
+		if err := res.manager.SetNamingStrategy(res.naming); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// DBWithDialect 指定数据库方言
+func DBWithDialect(dialect Dialect) DBOption {
+	return func(db *DB) {
+		db.dialect = dialect
+	}
+}
+
+// DBWithManager 指定表模型管理器
+// 用于在启动的时候通过 model.Register 提前注册好表模型，多个 DB 也可以共享同一个管理器
+func DBWithManager(manager *model.Manager) DBOption {
+	return func(db *DB) {
+		db.manager = manager
+	}
+}
+
+// DBWithNamingStrategy 指定命名策略
+// 命名策略是设置在表模型管理器上的，和 DBWithManager 一起使用的时候会修改传入的管理器
+// 管理器中已经有表模型或者已经冻结的时候，OpenDB 会返回错误
+func DBWithNamingStrategy(naming model.NamingStrategy) DBOption {
+	return func(db *DB) {
+		db.naming = naming
+	}
+}
+
+// DBWithValuer 指定默认的映射字段接口
+// NewSelectSQL 等语句传入的 valuer 为 nil 的时候，以及插入之后回填自增 ID 的时候使用
+func DBWithValuer(valuer valuer.FactoryValuer) DBOption {
+	return func(db *DB) {
+		db.valuer = valuer
+	}
+}
+
+func (d *DB) getCore() core {
+	return d.core
+}
+
+func (d *DB) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func (d *DB) execContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+// BeginTx 开启事务
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := d.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{core: d.core, tx: tx, savepointSeq: new(int)}, nil
+}
+
+// DoTx 在事务中执行 fn
+// fn 返回 nil 就提交事务，返回 error 或者发生 panic 就回滚事务
+// 发生 panic 的情况下，回滚之后会把 panic 继续往上抛
+func (d *DB) DoTx(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := d.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return doTx(tx, fn)
 }
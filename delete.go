@@ -2,7 +2,6 @@ package orm_framework
 
 import (
 	"context"
-	"github.com/borntodie-new/orm-framework/internal/errs"
 )
 
 var _ Executer = &DeleteSQL[any]{}
@@ -24,8 +23,8 @@ type DeleteSQL[T any] struct {
 	// model *model.Model
 
 	// manager *model.Manager
-	// db 全局唯一的连接对象
-	db *DB
+	// sess 执行语句的会话，可以是 DB 也可以是 Tx
+	sess Session
 	// builder 抽象出新的 SQL 构建器
 	*builder
 }
@@ -40,20 +39,19 @@ func (d *DeleteSQL[T]) Where(condition ...Predicate) *DeleteSQL[T] {
 
 // Build 构建SQL语句
 func (d *DeleteSQL[T]) Build() (*SQLInfo, error) {
+	d.builder = newBuilder(d.sess)
 	// 解析表模型
 	var err error
-	d.model, err = d.db.manager.Get(new(T))
+	d.model, err = d.sess.getCore().manager.Get(new(T))
 	if err != nil {
 		return nil, err
 	}
 	// 构建 DELETE 基本框架
 	d.sb.WriteString("DELETE FROM ")
 	// 构建 DELETE 的表名
-	d.sb.WriteByte('`')
-	d.sb.WriteString(d.model.TableName)
-	d.sb.WriteByte('`')
+	d.quote(d.model.TableName)
 	// 构建 WHERE 语句
-	if err = d.buildWhere(); err != nil {
+	if err = d.buildWhere(d.where); err != nil {
 		return nil, err
 	}
 	d.sb.WriteByte(';')
@@ -61,65 +59,6 @@ func (d *DeleteSQL[T]) Build() (*SQLInfo, error) {
 	return res, nil
 }
 
-func (d *DeleteSQL[T]) buildWhere() error {
-	if len(d.where) <= 0 {
-		return nil
-	}
-	d.sb.WriteString(" WHERE ")
-	p := d.where[0]
-	for i := 1; i < len(d.where)-1; i++ {
-		p = p.AND(d.where[i])
-	}
-	return d.buildFields(p)
-}
-
-// buildFields 构建WHERE语句
-func (d *DeleteSQL[T]) buildFields(exp Expression) error {
-	switch typ := exp.(type) {
-	case nil:
-		return nil
-	case Field:
-		// 这是纯字段
-		// 注意 Field传入的是Go中的字段名，设置到SQL上的是SQL中的列名
-		d.sb.WriteByte('(')
-		d.sb.WriteByte('`')
-		fd, ok := d.model.FieldsMap[typ.fieldName]
-		if !ok {
-			return errs.NewErrNotSupportUnknownField(typ.fieldName)
-		}
-		d.sb.WriteString(fd.ColumnName)
-		d.sb.WriteByte('`')
-	case Predicate:
-		// 这里需要递归实现，因为是 Predicate 类型，可能是 Field 也可能是 Value
-
-		// 构建左边
-		if err := d.buildFields(typ.left); err != nil {
-			return err
-		}
-		// 构建操作类型
-		d.sb.WriteString(typ.op.String())
-		// 构建右边
-		if err := d.buildFields(typ.right); err != nil {
-			return err
-		}
-	case Value:
-		// 这里是字段值
-		d.sb.WriteString("?")
-		d.addArgs(typ.val)
-		d.sb.WriteByte(')')
-	default:
-		return errs.ErrNotSupportPredicate
-	}
-	return nil
-}
-
-func (d *DeleteSQL[T]) addArgs(val any) {
-	if val == nil {
-		return
-	}
-	d.args = append(d.args, val)
-}
-
 // ExecuteWithContext 执行SQL语句
 // 这里返回的error是除SQL执行的错误的其他所有错误
 func (d *DeleteSQL[T]) ExecuteWithContext(ctx context.Context) (*Result, error) {
@@ -127,7 +66,7 @@ func (d *DeleteSQL[T]) ExecuteWithContext(ctx context.Context) (*Result, error)
 	if err != nil {
 		return nil, err
 	}
-	res, err := d.db.db.ExecContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := d.sess.execContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return &Result{
 			err: err,
@@ -139,13 +78,13 @@ func (d *DeleteSQL[T]) ExecuteWithContext(ctx context.Context) (*Result, error)
 
 // NewDeleteSQL 这是初始化一个 DeleteSQL 对象
 // 并且希望能够通过链式调用来使用
-func NewDeleteSQL[T any](db *DB) *DeleteSQL[T] {
+func NewDeleteSQL[T any](sess Session) *DeleteSQL[T] {
 	return &DeleteSQL[T]{
-		builder: newBuilder(),
+		builder: newBuilder(sess),
 		// sb:   &strings.Builder{},
 		// args: []any{},
 		// manager: &model.Manager{},
-		db: db,
+		sess: sess,
 	}
 }
 
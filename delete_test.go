@@ -77,6 +77,49 @@ func TestDeleteSQL_Build(t *testing.T) {
 			d:       NewDeleteSQL[TestModel](db).Where(NOT(F("Id").EQ(12)).AND(F("FirstName").EQ("Neo"))),
 			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE NOT (`id` = ?) AND (`first_name` = ?);", Args: []any{12, "Neo"}},
 		},
+		{
+			name:    "test NEQ condition",
+			d:       NewDeleteSQL[TestModel](db).Where(F("Id").NEQ(12)),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE (`id` != ?);", Args: []any{12}},
+		},
+		{
+			name:    "test NOT IN condition",
+			d:       NewDeleteSQL[TestModel](db).Where(F("Id").NotIn([]int64{1, 2, 3})),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE (`id` NOT IN (?, ?, ?));", Args: []any{int64(1), int64(2), int64(3)}},
+		},
+		{
+			name:    "test LIKE condition",
+			d:       NewDeleteSQL[TestModel](db).Where(F("FirstName").Like("%Neo%")),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE (`first_name` LIKE ?);", Args: []any{"%Neo%"}},
+		},
+		{
+			name:    "test IS NOT NULL condition",
+			d:       NewDeleteSQL[TestModel](db).Where(NOT(F("LastName").IsNotNull())),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE NOT (`test_model_last_name` IS NOT NULL);", Args: []any{}},
+		},
+		{
+			name:    "test column to column condition",
+			d:       NewDeleteSQL[TestModel](db).Where(F("Age").Mul(2).GT(F("Id"))),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE (`age` * ? > `id`);", Args: []any{2}},
+		},
+		{
+			name:    "test multiple where",
+			d:       NewDeleteSQL[TestModel](db).Where(F("Id").GT(1)).Where(F("Age").LT(10)),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE (`id` > ?) AND (`age` < ?);", Args: []any{1, 10}},
+		},
+		{
+			name:    "test NOT with OR",
+			d:       NewDeleteSQL[TestModel](db).Where(NOT(F("Id").EQ(1).OR(F("Age").EQ(2)))),
+			wantRes: &SQLInfo{SQL: "DELETE FROM `test_model` WHERE NOT ((`id` = ?) OR (`age` = ?));", Args: []any{1, 2}},
+		},
+		{
+			name: "test in query",
+			d:    NewDeleteSQL[TestModel](db).Where(F("Age").LT(18), F("Id").InQuery(NewSelectSQL[TestOrder](db, nil).Fields(F("UserId")).Where(F("Amount").EQ(0)))),
+			wantRes: &SQLInfo{
+				SQL:  "DELETE FROM `test_model` WHERE (`age` < ?) AND (`id` IN (SELECT `user_id` FROM `test_order` WHERE (`amount` = ?)));",
+				Args: []any{18, 0},
+			},
+		},
 		{
 			name:    "test not support unknown field",
 			d:       NewDeleteSQL[TestModel](db).Where(F("id").EQ(12).AND(F("FirstName").EQ("Neo"))),
@@ -97,6 +140,10 @@ func TestDeleteSQL_Build(t *testing.T) {
 				return
 			}
 			assert.Equal(t, tc.wantRes, res)
+			// 多次构建的结果是一样的
+			res, err = tc.d.Build()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
 		})
 	}
 }
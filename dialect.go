@@ -0,0 +1,237 @@
+package orm_framework
+
+import (
+	"database/sql"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/model"
+	"strconv"
+)
+
+// Dialect 方言
+// 不同的数据库在 SQL 语法上有些许差别，比如：
+// 1. 标识符的引号：MySQL 用的是 `，PostgreSQL 用的是 "
+// 2. 参数的占位符：MySQL 用的是 ?，PostgreSQL 用的是 $1、$2
+// 3. 分页的语法：只有 OFFSET 没有 LIMIT 的时候，MySQL 和 SQLite 都要求必须有 LIMIT
+// 这些差别都交给 Dialect 处理，builder 在构建 SQL 的时候统一通过 Dialect 写入
+// 自定义方言的时候嵌入 StandardSQL，只覆盖有差别的方法就可以了，比如 SQL Server 的占位符 @p1、@p2
+type Dialect interface {
+	// Quoter 标识符的引号
+	Quoter() byte
+	// Placeholder 第 idx 个参数的占位符，idx 从 1 开始
+	Placeholder(idx int) string
+	// BuildLimit 构建分页子句，limit 或者 offset 为 0 表示没有设置
+	BuildLimit(b SQLBuilder, limit int, offset int)
+	// FirstInsertId 批量插入 rows 行数据之后，第一行数据的自增 ID
+	// 不同的数据库 LastInsertId 的含义不一样，MySQL 返回的是第一行的 ID，SQLite 返回的是最后一行的 ID
+	// 返回 0 表示不支持获取自增 ID，这个时候不会回填
+	FirstInsertId(res sql.Result, rows int64) (int64, error)
+	// BuildUpsert 构建插入冲突的时候的处理子句
+	// MySQL 用的是 ON DUPLICATE KEY UPDATE，SQLite 和 PostgreSQL 用的是 ON CONFLICT
+	BuildUpsert(b SQLBuilder, u *Upsert) error
+	// BuildExcluded 引用插入的时候冲突的那一行数据的值
+	BuildExcluded(b SQLBuilder, column string)
+}
+
+// SQLBuilder 方言构建 SQL 片段的时候使用的构造器
+// 只暴露方言需要的功能，引号和占位符也是通过方言处理的
+type SQLBuilder interface {
+	// WriteString 写入 SQL 片段
+	WriteString(s string)
+	// Quote 写入带引号的标识符，比如列名
+	Quote(name string)
+	// AddArg 添加参数，同时写入对应的占位符
+	AddArg(val any)
+	// Model 当前语句的表模型
+	Model() *model.Model
+	// BuildAssignments 构建赋值列表，比如 `name` = ?, `count` = `count` + ?
+	// qualifier 不为空的时候，赋值右边没有指定表的列都会带上这个表名
+	BuildAssignments(assigns []Assignment, qualifier string) error
+}
+
+// sqlBuilder SQLBuilder 的实现
+// 没有直接让 builder 实现 SQLBuilder，是因为 builder 被嵌入到了各个语句中，导出的方法会变成语句的方法
+type sqlBuilder struct {
+	b *builder
+}
+
+func (s sqlBuilder) WriteString(str string) {
+	s.b.sb.WriteString(str)
+}
+
+func (s sqlBuilder) Quote(name string) {
+	s.b.quote(name)
+}
+
+func (s sqlBuilder) AddArg(val any) {
+	s.b.addArgs(val)
+}
+
+func (s sqlBuilder) Model() *model.Model {
+	return s.b.model
+}
+
+func (s sqlBuilder) BuildAssignments(assigns []Assignment, qualifier string) error {
+	origin := s.b.qualifier
+	s.b.qualifier = qualifier
+	defer func() {
+		s.b.qualifier = origin
+	}()
+	return s.b.buildAssignments(assigns)
+}
+
+var (
+	MySQL      Dialect = mysqlDialect{}
+	SQLite3    Dialect = sqlite3Dialect{}
+	PostgreSQL Dialect = postgresDialect{}
+)
+
+// StandardSQL 标准 SQL 的实现，具体的方言在这个基础上覆盖有差别的部分
+type StandardSQL struct {
+}
+
+func (s StandardSQL) Quoter() byte {
+	return '"'
+}
+
+func (s StandardSQL) Placeholder(idx int) string {
+	return "?"
+}
+
+func (s StandardSQL) BuildLimit(b SQLBuilder, limit int, offset int) {
+	if limit > 0 {
+		b.WriteString(" LIMIT ")
+		b.AddArg(limit)
+	}
+	if offset > 0 {
+		b.WriteString(" OFFSET ")
+		b.AddArg(offset)
+	}
+}
+
+// FirstInsertId 默认按照 MySQL 的语义处理，LastInsertId 就是第一行的 ID
+// 注意：这里假设批量插入的 ID 是连续的，MySQL 的 innodb_autoinc_lock_mode 为 0 或者 1 的时候是成立的
+func (s StandardSQL) FirstInsertId(res sql.Result, rows int64) (int64, error) {
+	return res.LastInsertId()
+}
+
+// BuildUpsert 标准 SQL 的实现，SQLite 和 PostgreSQL 都是这个语法
+// ON CONFLICT (`email`) DO UPDATE SET `name` = excluded.`name`, `count` = `user`.`count` + excluded.`count`
+// ON CONFLICT (`email`) DO NOTHING
+func (s StandardSQL) BuildUpsert(b SQLBuilder, u *Upsert) error {
+	b.WriteString(" ON CONFLICT")
+	if len(u.ConflictFields) > 0 {
+		b.WriteString(" (")
+		for idx, fieldName := range u.ConflictFields {
+			if idx > 0 {
+				b.WriteString(", ")
+			}
+			fd, ok := b.Model().FieldsMap[fieldName]
+			if !ok {
+				return errs.NewErrNotSupportUnknownField(fieldName)
+			}
+			b.Quote(fd.ColumnName)
+		}
+		b.WriteString(")")
+	}
+	if u.DoNothing {
+		b.WriteString(" DO NOTHING")
+		return nil
+	}
+	// PostgreSQL 要求 DO UPDATE 必须指定冲突的列
+	if len(u.ConflictFields) == 0 {
+		return errs.ErrUpsertNoConflictFields
+	}
+	b.WriteString(" DO UPDATE SET ")
+	// 赋值的右边引用的是表中原来的值，需要带上表名，否则和 excluded 中的列有歧义
+	// 赋值的左边不能带表名，BuildAssignments 构建左边的时候不会带上 qualifier
+	return b.BuildAssignments(u.Assigns, b.Model().TableName)
+}
+
+func (s StandardSQL) BuildExcluded(b SQLBuilder, column string) {
+	b.WriteString("excluded.")
+	b.Quote(column)
+}
+
+type mysqlDialect struct {
+	StandardSQL
+}
+
+func (m mysqlDialect) Quoter() byte {
+	return '`'
+}
+
+func (m mysqlDialect) BuildLimit(b SQLBuilder, limit int, offset int) {
+	// MySQL 不支持单独使用 OFFSET，官方推荐的做法是用一个足够大的数作为 LIMIT
+	if limit <= 0 && offset > 0 {
+		b.WriteString(" LIMIT 18446744073709551615")
+	}
+	m.StandardSQL.BuildLimit(b, limit, offset)
+}
+
+// BuildUpsert MySQL 是根据所有的主键和唯一索引判断冲突的，所以会忽略冲突的字段
+// ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)
+func (m mysqlDialect) BuildUpsert(b SQLBuilder, u *Upsert) error {
+	b.WriteString(" ON DUPLICATE KEY UPDATE ")
+	if !u.DoNothing {
+		return b.BuildAssignments(u.Assigns, "")
+	}
+	// MySQL 没有 DO NOTHING，把某一列设置成它自己就相当于什么都不做
+	// 不用 INSERT IGNORE 是因为它会把其他的错误也忽略掉
+	fd := b.Model().Fields[0]
+	if len(u.ConflictFields) > 0 {
+		var ok bool
+		if fd, ok = b.Model().FieldsMap[u.ConflictFields[0]]; !ok {
+			return errs.NewErrNotSupportUnknownField(u.ConflictFields[0])
+		}
+	} else if len(b.Model().PrimaryKeys) > 0 {
+		fd = b.Model().PrimaryKeys[0]
+	}
+	b.Quote(fd.ColumnName)
+	b.WriteString(" = ")
+	b.Quote(fd.ColumnName)
+	return nil
+}
+
+func (m mysqlDialect) BuildExcluded(b SQLBuilder, column string) {
+	b.WriteString("VALUES(")
+	b.Quote(column)
+	b.WriteString(")")
+}
+
+type sqlite3Dialect struct {
+	StandardSQL
+}
+
+func (s sqlite3Dialect) Quoter() byte {
+	return '`'
+}
+
+func (s sqlite3Dialect) BuildLimit(b SQLBuilder, limit int, offset int) {
+	// SQLite 同样不支持单独使用 OFFSET，LIMIT -1 表示不限制
+	if limit <= 0 && offset > 0 {
+		b.WriteString(" LIMIT -1")
+	}
+	s.StandardSQL.BuildLimit(b, limit, offset)
+}
+
+// FirstInsertId SQLite 的 LastInsertId 是最后一行的 ID，往前推就是第一行的 ID
+func (s sqlite3Dialect) FirstInsertId(res sql.Result, rows int64) (int64, error) {
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return id - rows + 1, nil
+}
+
+type postgresDialect struct {
+	StandardSQL
+}
+
+func (p postgresDialect) Placeholder(idx int) string {
+	return "$" + strconv.Itoa(idx)
+}
+
+// FirstInsertId PostgreSQL 的驱动不支持 LastInsertId，需要使用 RETURNING，所以这里不回填
+func (p postgresDialect) FirstInsertId(res sql.Result, rows int64) (int64, error) {
+	return 0, nil
+}
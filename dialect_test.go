@@ -0,0 +1,189 @@
+package orm_framework
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestDialect_Build(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	pg, err := OpenDB(mockDB, DBWithDialect(PostgreSQL))
+	assert.NoError(t, err)
+	sqlite, err := OpenDB(mockDB, DBWithDialect(SQLite3))
+	assert.NoError(t, err)
+	mssql, err := OpenDB(mockDB, DBWithDialect(mssqlDialect{}))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		b       Builder
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test postgres delete",
+			b:    NewDeleteSQL[TestModel](pg).Where(F("Id").EQ(12).AND(F("FirstName").EQ("Neo"))),
+			wantRes: &SQLInfo{
+				SQL:  `DELETE FROM "test_model" WHERE ("id" = $1) AND ("first_name" = $2);`,
+				Args: []any{12, "Neo"},
+			},
+		},
+		{
+			name: "test postgres update",
+			b:    NewUpdateSQL[TestModel](pg).Values("FirstName", "Neo").Values("Age", 18).Where(F("Id").EQ(12)),
+			wantRes: &SQLInfo{
+				SQL:  `UPDATE "test_model" SET "first_name" = $1, "age" = $2 WHERE ("id" = $3);`,
+				Args: []any{"Neo", 18, 12},
+			},
+		},
+		{
+			name: "test postgres insert",
+			b:    NewInsertSQL[TestModel](pg).Fields("Id", "FirstName").Values(&TestModel{Id: 1, FirstName: "Neo"}, &TestModel{Id: 2, FirstName: "Jason"}),
+			wantRes: &SQLInfo{
+				SQL:  `INSERT INTO "test_model" ("id", "first_name") VALUES ($1, $2), ($3, $4);`,
+				Args: []any{int8(1), "Neo", int8(2), "Jason"},
+			},
+		},
+		{
+			name: "test postgres select",
+			b:    NewSelectSQL[TestModel](pg, nil).Fields(Avg("Age").As("avg_age")).Where(F("Id").GT(12)),
+			wantRes: &SQLInfo{
+				SQL:  `SELECT AVG("age") AS "avg_age" FROM "test_model" WHERE ("id" > $1);`,
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test postgres select with limit",
+			b:    NewSelectSQL[TestModel](pg, nil).Where(F("Id").GT(12)).OrderBy(Desc("Id")).Limit(10).Offset(20),
+			wantRes: &SQLInfo{
+				SQL:  `SELECT * FROM "test_model" WHERE ("id" > $1) ORDER BY "id" DESC LIMIT $2 OFFSET $3;`,
+				Args: []any{12, 10, 20},
+			},
+		},
+		{
+			name: "test postgres select with subquery",
+			b: NewSelectSQL[TestModel](pg, nil).Where(F("Age").GT(18),
+				F("Id").InQuery(NewSelectSQL[TestOrder](pg, nil).Fields(F("UserId")).Where(F("Amount").GT(100))), F("FirstName").EQ("Neo")),
+			wantRes: &SQLInfo{
+				SQL:  `SELECT * FROM "test_model" WHERE ("age" > $1) AND ("id" IN (SELECT "user_id" FROM "test_order" WHERE ("amount" > $2))) AND ("first_name" = $3);`,
+				Args: []any{18, 100, "Neo"},
+			},
+		},
+		{
+			name: "test sqlite select",
+			b:    NewSelectSQL[TestModel](sqlite, nil).Fields(Common("Id")).Where(F("Id").GT(12)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `id` FROM `test_model` WHERE (`id` > ?);",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test custom dialect select",
+			b:    NewSelectSQL[TestModel](mssql, nil).Where(F("Id").GT(12), F("FirstName").EQ("Neo")).OrderBy(Desc("Id")).Limit(10).Offset(20),
+			wantRes: &SQLInfo{
+				SQL:  `SELECT * FROM "test_model" WHERE ("id" > @p1) AND ("first_name" = @p2) ORDER BY "id" DESC OFFSET @p3 ROWS FETCH NEXT @p4 ROWS ONLY;`,
+				Args: []any{12, "Neo", 20, 10},
+			},
+		},
+		{
+			name: "test custom dialect update",
+			b:    NewUpdateSQL[TestModel](mssql).Values("FirstName", "Neo").Where(F("Id").EQ(12)),
+			wantRes: &SQLInfo{
+				SQL:  `UPDATE "test_model" SET "first_name" = @p1 WHERE ("id" = @p2);`,
+				Args: []any{"Neo", 12},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.b.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestDialect_BuildLimit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		limit   int
+		offset  int
+		wantSQL string
+	}{
+		{
+			name:    "test mysql limit and offset",
+			dialect: MySQL,
+			limit:   10,
+			offset:  20,
+			wantSQL: " LIMIT ? OFFSET ?",
+		},
+		{
+			name:    "test mysql only offset",
+			dialect: MySQL,
+			offset:  20,
+			wantSQL: " LIMIT 18446744073709551615 OFFSET ?",
+		},
+		{
+			name:    "test sqlite only offset",
+			dialect: SQLite3,
+			offset:  20,
+			wantSQL: " LIMIT -1 OFFSET ?",
+		},
+		{
+			name:    "test postgres only offset",
+			dialect: PostgreSQL,
+			offset:  20,
+			wantSQL: " OFFSET $1",
+		},
+		{
+			name:    "test postgres limit and offset",
+			dialect: PostgreSQL,
+			limit:   10,
+			offset:  20,
+			wantSQL: " LIMIT $1 OFFSET $2",
+		},
+		{
+			name:    "test no limit",
+			dialect: PostgreSQL,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &DB{core: core{dialect: tc.dialect}}
+			b := newBuilder(db)
+			tc.dialect.BuildLimit(sqlBuilder{b: b}, tc.limit, tc.offset)
+			assert.Equal(t, tc.wantSQL, b.sb.String())
+		})
+	}
+}
+
+// mssqlDialect 在包外自定义方言的写法，嵌入 StandardSQL，只覆盖有差别的部分
+type mssqlDialect struct {
+	StandardSQL
+}
+
+func (m mssqlDialect) Placeholder(idx int) string {
+	return "@p" + strconv.Itoa(idx)
+}
+
+// BuildLimit SQL Server 用的是 OFFSET ... ROWS FETCH NEXT ... ROWS ONLY
+func (m mssqlDialect) BuildLimit(b SQLBuilder, limit int, offset int) {
+	if limit <= 0 && offset <= 0 {
+		return
+	}
+	b.WriteString(" OFFSET ")
+	b.AddArg(offset)
+	b.WriteString(" ROWS")
+	if limit > 0 {
+		b.WriteString(" FETCH NEXT ")
+		b.AddArg(limit)
+		b.WriteString(" ROWS ONLY")
+	}
+}
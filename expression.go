@@ -1,5 +1,7 @@
 package orm_framework
 
+import "reflect"
+
 // Expression 表达式，其实就是一个标记位，用于约束表达式的类型的
 type Expression interface {
 	expr()
@@ -20,3 +22,53 @@ func (v Value) expr() {
 func valueOf(val any) Value {
 	return Value{val: val}
 }
+
+// exprOf 如果 val 本身就是 Expression 就直接用，比如 F("UpdatedAt").GT(F("CreatedAt"))
+// 如果 val 是一个查询语句，就当作子查询处理，比如 F("Age").GT(NewSelectSQL[User](db, nil).Fields(Avg("Age")))
+// 否则就当作普通的值处理
+func exprOf(val any) Expression {
+	switch typ := val.(type) {
+	case Expression:
+		return typ
+	case QueryBuilder:
+		return Subquery{q: typ}
+	default:
+		return valueOf(val)
+	}
+}
+
+// valueList 多个值，作为 IN、NOT IN 的 right
+// 构建的时候会展开成 (?, ?, ?)
+type valueList struct {
+	vals []any
+}
+
+// expr 标记位
+func (v valueList) expr() {}
+
+// valueListOf 初始化 valueList
+// 如果只传入了一个切片，比如 In([]int{1, 2, 3})，就把切片展开
+func valueListOf(vals []any) valueList {
+	if len(vals) == 1 {
+		val := reflect.ValueOf(vals[0])
+		// []byte 是一个完整的值，不能展开
+		if val.Kind() == reflect.Slice && val.Type().Elem().Kind() != reflect.Uint8 {
+			res := make([]any, 0, val.Len())
+			for i := 0; i < val.Len(); i++ {
+				res = append(res, val.Index(i).Interface())
+			}
+			return valueList{vals: res}
+		}
+	}
+	return valueList{vals: vals}
+}
+
+// betweenValue BETWEEN 的两个值，作为 BETWEEN 的 right
+// 构建的时候会展开成 ? AND ?
+type betweenValue struct {
+	start any
+	end   any
+}
+
+// expr 标记位
+func (b betweenValue) expr() {}
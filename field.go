@@ -1,7 +1,12 @@
 package orm_framework
 
 type Field struct {
+	// table 字段所属的表，为空表示属于语句本身的表模型
+	table TableReference
+	// fieldName Go中结构体的字段名
 	fieldName string
+	// alias 列别名，只在 SELECT 列表中生效
+	alias string
 }
 
 // expr 完全是一个标记位，不做任何事情
@@ -15,39 +20,160 @@ func F(fieldName string) Field {
 	return Field{fieldName: fieldName}
 }
 
+// selectable 标记位，Field 可以直接出现在 SELECT 列表中
+func (f Field) selectable() {}
+
+// As 设置列别名
+// Go中的使用：TableOf[User]().As("u").F("Name").As("user_name")
+// SQL中的使用：`u`.`name` AS `user_name`
+func (f Field) As(alias string) Field {
+	f.alias = alias
+	return f
+}
+
 func (f Field) EQ(val any) Predicate {
 	return Predicate{
 		left:  f,
 		op:    EQType,
-		right: valueOf(val),
+		right: exprOf(val),
+	}
+}
+func (f Field) NEQ(val any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    NEQType,
+		right: exprOf(val),
 	}
 }
 func (f Field) GT(val any) Predicate {
 	return Predicate{
 		left:  f,
 		op:    GTType,
-		right: valueOf(val),
+		right: exprOf(val),
 	}
 }
 func (f Field) GTE(val any) Predicate {
 	return Predicate{
 		left:  f,
 		op:    GTEType,
-		right: valueOf(val),
+		right: exprOf(val),
 	}
 }
 func (f Field) LT(val any) Predicate {
 	return Predicate{
 		left:  f,
 		op:    LTType,
-		right: valueOf(val),
+		right: exprOf(val),
 	}
 }
 func (f Field) LTE(val any) Predicate {
 	return Predicate{
 		left:  f,
 		op:    LTEType,
-		right: valueOf(val),
+		right: exprOf(val),
+	}
+}
+
+// Add Go中的使用：F("Stock").Add(1)
+// SQL中的使用：`stock` + ?
+func (f Field) Add(val any) MathExpr {
+	return MathExpr{
+		left:  f,
+		op:    ADDType,
+		right: exprOf(val),
+	}
+}
+func (f Field) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  f,
+		op:    SUBType,
+		right: exprOf(val),
+	}
+}
+func (f Field) Mul(val any) MathExpr {
+	return MathExpr{
+		left:  f,
+		op:    MULType,
+		right: exprOf(val),
+	}
+}
+func (f Field) Div(val any) MathExpr {
+	return MathExpr{
+		left:  f,
+		op:    DIVType,
+		right: exprOf(val),
+	}
+}
+
+// In Go中的使用：F("Id").In(1, 2, 3) 或者 F("Id").In([]int{1, 2, 3})
+// SQL中的使用：`id` IN (?, ?, ?)
+func (f Field) In(vals ...any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    INType,
+		right: valueListOf(vals),
+	}
+}
+func (f Field) NotIn(vals ...any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    NOTINType,
+		right: valueListOf(vals),
+	}
+}
+
+// InQuery Go中的使用：F("Id").InQuery(NewSelectSQL[Order](db, nil).Fields(F("UserId")))
+// SQL中的使用：`id` IN (SELECT `user_id` FROM `order`)
+func (f Field) InQuery(q QueryBuilder) Predicate {
+	return Predicate{
+		left:  f,
+		op:    INType,
+		right: Subquery{q: q},
+	}
+}
+func (f Field) NotInQuery(q QueryBuilder) Predicate {
+	return Predicate{
+		left:  f,
+		op:    NOTINType,
+		right: Subquery{q: q},
+	}
+}
+func (f Field) Like(pattern string) Predicate {
+	return Predicate{
+		left:  f,
+		op:    LIKEType,
+		right: valueOf(pattern),
+	}
+}
+func (f Field) NotLike(pattern string) Predicate {
+	return Predicate{
+		left:  f,
+		op:    NOTLIKEType,
+		right: valueOf(pattern),
+	}
+}
+
+// Between Go中的使用：F("Age").Between(18, 30)
+// SQL中的使用：`age` BETWEEN ? AND ?
+func (f Field) Between(start any, end any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    BETWEENType,
+		right: betweenValue{start: start, end: end},
+	}
+}
+
+// IsNull 这种操作符是没有 right 的
+func (f Field) IsNull() Predicate {
+	return Predicate{
+		left: f,
+		op:   ISNULLType,
+	}
+}
+func (f Field) IsNotNull() Predicate {
+	return Predicate{
+		left: f,
+		op:   ISNOTNULLType,
 	}
 }
 
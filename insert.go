@@ -2,6 +2,7 @@ package orm_framework
 
 import (
 	"context"
+	"database/sql"
 	"github.com/borntodie-new/orm-framework/internal/errs"
 	"github.com/borntodie-new/orm-framework/model"
 	"reflect"
@@ -15,19 +16,26 @@ type InsertSQL[T any] struct {
 	// sb *strings.Builder
 	// args SQL语句中的参数
 	// args []any
-	// db 全局的、自定义的数据库连接对象
-	db *DB
-	// values 插入的具体的值
-	values []T
+	// sess 执行语句的会话，可以是 DB 也可以是 Tx
+	sess Session
+	// values 插入的具体的值，用指针是为了插入之后能够回填自增 ID
+	values []*T
 	// fields 指定需要插入的字段名 Go 中的
 	fields []string
+	// backfill 构建的时候没有插入自增列，执行之后需要回填自增 ID
+	backfill bool
+	// upsert 插入冲突的时候怎么处理，为 nil 表示不处理
+	upsert *Upsert
 	// model 维护一个表模型
 	// model *model.Model
 	// builder 抽象出新的 SQL 构造器
 	*builder
 }
 
-func (i *InsertSQL[T]) Values(values ...T) *InsertSQL[T] {
+// Values 指定插入的数据
+// 有自增列并且自增列的值为零的时候，不会插入这一列，执行之后会把生成的 ID 回填到 values 中
+// Go中的使用：NewInsertSQL[User](db).Values(&User{Name: "Neo"}, &User{Name: "Jason"})
+func (i *InsertSQL[T]) Values(values ...*T) *InsertSQL[T] {
 	i.values = append(i.values, values...)
 	return i
 }
@@ -37,14 +45,6 @@ func (i *InsertSQL[T]) Fields(fields ...string) *InsertSQL[T] {
 	return i
 }
 
-// addArgs 添加SQL参数
-func (i *InsertSQL[T]) addArgs(val any) {
-	if val == nil {
-		return
-	}
-	i.args = append(i.args, val)
-}
-
 // buildValues 构建 VALUES 子句
 // 该函数的重要功能如下
 // 1. 构建 len(i.values)个(?,?,?,...)
@@ -64,7 +64,18 @@ func (i *InsertSQL[T]) buildColumnsAndValues() error {
 	}
 	// 如果用户没有指定字段顺序，就用默认的
 	if len(i.fields) == 0 {
-		orderFields = i.model.Fields
+		var err error
+		if orderFields, err = i.defaultFields(); err != nil {
+			return err
+		}
+	}
+	// 没有插入自增列的时候，由数据库生成 ID，执行之后需要回填
+	// upsert 的时候有的行可能是更新的，LastInsertId 就对不上了，所以不回填
+	i.backfill = i.model.AutoIncrement != nil && i.upsert == nil
+	for _, field := range orderFields {
+		if field == i.model.AutoIncrement {
+			i.backfill = false
+		}
 	}
 
 	// 构建具体的列名
@@ -73,18 +84,18 @@ func (i *InsertSQL[T]) buildColumnsAndValues() error {
 		if idx > 0 {
 			i.sb.WriteString(", ")
 		}
-		i.sb.WriteByte('`')
-		i.sb.WriteString(field.ColumnName)
-		i.sb.WriteByte('`')
+		i.quote(field.ColumnName)
 	}
 	i.sb.WriteByte(')')
 
 	// 构建占位符
-	// len(orderFields)*len(i.values) 计算出要有多少个参数，就有多少个?占位符
+	// len(orderFields)*len(i.values) 计算出要有多少个参数，就有多少个占位符
 	i.sb.WriteString(" VALUES ")
-	fieldArgs := make([]any, 0, len(orderFields)*len(i.values))
 	for idx, value := range i.values {
 		val := reflect.Indirect(reflect.ValueOf(value))
+		if !val.IsValid() {
+			return errs.ErrUnsupportedNil
+		}
 		if idx > 0 {
 			i.sb.WriteString(", ")
 		}
@@ -93,65 +104,124 @@ func (i *InsertSQL[T]) buildColumnsAndValues() error {
 			if count > 0 {
 				i.sb.WriteString(", ")
 			}
-			fd := val.FieldByName(field.FieldName)
-			// 构建占位符 ？
-			i.sb.WriteByte('?')
-			// 存储字段数据
-			fieldArgs = append(fieldArgs, fd.Interface())
+			fd := val.FieldByIndex(field.FieldIndex)
+			// 构建占位符，同时存储字段数据
+			// 注意：占位符必须和参数一一对应着构建，因为 PostgreSQL 的占位符是带序号的
+			i.addArgs(nullable(fd.Interface()))
 		}
 		i.sb.WriteByte(')')
 	}
-	i.args = append(i.args, fieldArgs...)
 	return nil
 }
 
+// defaultFields 没有指定字段的时候插入的字段
+// 1. 只读列不插入
+// 2. 自增列的值都是零的时候不插入，交给数据库生成
+func (i *InsertSQL[T]) defaultFields() ([]*model.Field, error) {
+	auto := i.model.AutoIncrement
+	if auto != nil {
+		zero := 0
+		for _, value := range i.values {
+			val := reflect.Indirect(reflect.ValueOf(value))
+			if !val.IsValid() {
+				return nil, errs.ErrUnsupportedNil
+			}
+			if val.FieldByIndex(auto.FieldIndex).IsZero() {
+				zero++
+			}
+		}
+		// 批量插入的时候一部分有值一部分没有值，列就对不上了
+		if zero > 0 && zero != len(i.values) {
+			return nil, errs.ErrMixedAutoIncrementValues
+		}
+		// 自增列有值的时候需要插入
+		if zero == 0 {
+			auto = nil
+		}
+	}
+	fields := make([]*model.Field, 0, len(i.model.Fields))
+	for _, field := range i.model.Fields {
+		if field != auto && !field.Readonly {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
 // ExecuteWithContext 执行SQL语句
 func (i *InsertSQL[T]) ExecuteWithContext(ctx context.Context) (*Result, error) {
 	sqlInfo, err := i.Build()
 	if err != nil {
 		return nil, err
 	}
-	res, err := i.db.db.ExecContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := i.sess.execContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return &Result{
 			err: err,
 			res: nil,
 		}, nil
 	}
+	if i.backfill {
+		if err = i.backfillIds(res); err != nil {
+			return &Result{err: err, res: res}, nil
+		}
+	}
 	return &Result{res: res}, err
 }
 
+// backfillIds 把数据库生成的自增 ID 回填到 values 中
+// 批量插入的 ID 是连续的，所以只需要知道第一行的 ID 就可以了
+func (i *InsertSQL[T]) backfillIds(res sql.Result) error {
+	c := i.sess.getCore()
+	id, err := c.dialect.FirstInsertId(res, int64(len(i.values)))
+	if err != nil || id == 0 {
+		return err
+	}
+	for idx, value := range i.values {
+		val := c.valuer(i.model, value)
+		if err = val.SetFieldValue(i.model.AutoIncrement.FieldName, id+int64(idx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Build 构造SQL语句和维护SQL参数
 // INSERT INTO `test_model` (`id`, `first_name`, `age`, `last_name`) VALUES (?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?), (?, ?, ?, ?);
 func (i *InsertSQL[T]) Build() (*SQLInfo, error) {
+	i.builder = newBuilder(i.sess)
 	// 构建SQL基本架构
 	i.sb.WriteString("INSERT INTO ")
 	var err error
-	i.model, err = i.db.manager.Get(new(T))
+	i.model, err = i.sess.getCore().manager.Get(new(T))
 	if err != nil {
 		return nil, err
 	}
 	// 构建表名
-	i.sb.WriteByte('`')
-	i.sb.WriteString(i.model.TableName)
-	i.sb.WriteByte('`')
+	i.quote(i.model.TableName)
 	i.sb.WriteByte(' ')
 
 	// TODO 构建COLUMNS 和 VALUES语句
 	if err = i.buildColumnsAndValues(); err != nil {
 		return nil, err
 	}
+	// 构建插入冲突的时候的处理子句
+	if i.upsert != nil {
+		if err = i.sess.getCore().dialect.BuildUpsert(sqlBuilder{b: i.builder}, i.upsert); err != nil {
+			return nil, err
+		}
+	}
 
 	i.sb.WriteByte(';')
 	res := &SQLInfo{SQL: i.sb.String(), Args: i.args}
 	return res, nil
 }
 
-func NewInsertSQL[T any](db *DB) *InsertSQL[T] {
+func NewInsertSQL[T any](sess Session) *InsertSQL[T] {
 	return &InsertSQL[T]{
 		// sb:   &strings.Builder{},
 		// args: []any{},
-		builder: newBuilder(),
-		db:      db,
+		builder: newBuilder(sess),
+		sess:    sess,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -22,7 +23,7 @@ func TestInsertSQL_Build(t *testing.T) {
 	}{
 		{
 			name: "test one values with full fields",
-			i: NewInsertSQL[TestModel](db).Values(TestModel{
+			i: NewInsertSQL[TestModel](db).Values(&TestModel{
 				Id:        1,
 				FirstName: "Jason",
 				Age:       19,
@@ -35,12 +36,12 @@ func TestInsertSQL_Build(t *testing.T) {
 		},
 		{
 			name: "test multiple values with full fields",
-			i: NewInsertSQL[TestModel](db).Values(TestModel{
+			i: NewInsertSQL[TestModel](db).Values(&TestModel{
 				Id:        1,
 				FirstName: "Jason",
 				Age:       19,
 				LastName:  &sql.NullString{Valid: true, String: "Neo"},
-			}, TestModel{
+			}, &TestModel{
 				Id:        100,
 				FirstName: "Tank",
 				Age:       67,
@@ -56,7 +57,7 @@ func TestInsertSQL_Build(t *testing.T) {
 		},
 		{
 			name: "test one values with specially fields",
-			i: NewInsertSQL[TestModel](db).Fields("Id", "LastName").Values(TestModel{
+			i: NewInsertSQL[TestModel](db).Fields("Id", "LastName").Values(&TestModel{
 				Id:       1,
 				LastName: &sql.NullString{Valid: true, String: "Neo"},
 			}),
@@ -67,10 +68,10 @@ func TestInsertSQL_Build(t *testing.T) {
 		},
 		{
 			name: "test multiple values with specially fields",
-			i: NewInsertSQL[TestModel](db).Fields("Id", "LastName").Values(TestModel{
+			i: NewInsertSQL[TestModel](db).Fields("Id", "LastName").Values(&TestModel{
 				Id:       1,
 				LastName: &sql.NullString{Valid: true, String: "Neo"},
-			}, TestModel{
+			}, &TestModel{
 				Id:       100,
 				LastName: &sql.NullString{Valid: true, String: "JASON"},
 			}),
@@ -84,7 +85,7 @@ func TestInsertSQL_Build(t *testing.T) {
 		},
 		{
 			name: "test multiple values with specially fields",
-			i: NewInsertSQL[TestModel](db).Fields("Invalid").Values(TestModel{
+			i: NewInsertSQL[TestModel](db).Fields("Invalid").Values(&TestModel{
 				Id:       1,
 				LastName: &sql.NullString{Valid: true, String: "Neo"},
 			}),
@@ -123,7 +124,7 @@ func TestInsertSQL_ExecuteWithContext(t *testing.T) {
 			prepareSQL: func() {
 				mock.ExpectExec("INSERT INTO .*").WillReturnError(errors.New("no db"))
 			},
-			i:       NewInsertSQL[TestModel](db).Values(TestModel{}),
+			i:       NewInsertSQL[TestModel](db).Values(&TestModel{}),
 			wantErr: errors.New("no db"),
 		},
 		{
@@ -132,7 +133,7 @@ func TestInsertSQL_ExecuteWithContext(t *testing.T) {
 				result := driver.RowsAffected(19)
 				mock.ExpectExec("INSERT INTO .*").WillReturnResult(result)
 			},
-			i:        NewInsertSQL[TestModel](db).Values(TestModel{}, TestModel{}),
+			i:        NewInsertSQL[TestModel](db).Values(&TestModel{}, &TestModel{}),
 			affected: int64(19),
 		},
 	}
@@ -152,3 +153,146 @@ func TestInsertSQL_ExecuteWithContext(t *testing.T) {
 		})
 	}
 }
+
+type TestAutoModel struct {
+	Id   int32 `orm:"pk,auto_increment"`
+	Name string
+}
+
+type TestMultiAutoModel struct {
+	Id  int64 `orm:"auto_increment"`
+	Seq int64 `orm:"auto_increment"`
+}
+
+func TestInsertSQL_AutoIncrementBuild(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name    string
+		i       Builder
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test omit zero auto increment",
+			i:    NewInsertSQL[TestAutoModel](db).Values(&TestAutoModel{Name: "Neo"}, &TestAutoModel{Name: "Jason"}),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_auto_model` (`name`) VALUES (?), (?);",
+				Args: []any{"Neo", "Jason"},
+			},
+		},
+		{
+			name: "test keep non zero auto increment",
+			i:    NewInsertSQL[TestAutoModel](db).Values(&TestAutoModel{Id: 10, Name: "Neo"}),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_auto_model` (`id`, `name`) VALUES (?, ?);",
+				Args: []any{int32(10), "Neo"},
+			},
+		},
+		{
+			name: "test specially fields",
+			i:    NewInsertSQL[TestAutoModel](db).Fields("Id", "Name").Values(&TestAutoModel{Name: "Neo"}),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_auto_model` (`id`, `name`) VALUES (?, ?);",
+				Args: []any{int32(0), "Neo"},
+			},
+		},
+		{
+			name:    "test mixed auto increment values",
+			i:       NewInsertSQL[TestAutoModel](db).Values(&TestAutoModel{Id: 10}, &TestAutoModel{}),
+			wantErr: errs.ErrMixedAutoIncrementValues,
+		},
+		{
+			name:    "test nil value",
+			i:       NewInsertSQL[TestAutoModel](db).Values(nil),
+			wantErr: errs.ErrUnsupportedNil,
+		},
+		{
+			name:    "test nil value without auto increment",
+			i:       NewInsertSQL[TestModel](db).Values(&TestModel{}, nil),
+			wantErr: errs.ErrUnsupportedNil,
+		},
+		{
+			name:    "test multiple auto increment",
+			i:       NewInsertSQL[TestMultiAutoModel](db).Values(&TestMultiAutoModel{}),
+			wantErr: errs.ErrMultipleAutoIncrement,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.i.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestInsertSQL_AutoIncrementBackfill(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// MySQL 的 LastInsertId 是第一行的 ID
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	for _, v := range []valuer.FactoryValuer{valuer.NewUnsafeValuer, valuer.NewReflectValuer} {
+		db, err := OpenDB(mockDB, DBWithValuer(v))
+		assert.NoError(t, err)
+		mock.ExpectExec("INSERT INTO `test_auto_model` \\(`name`\\) VALUES \\(\\?\\), \\(\\?\\), \\(\\?\\);").
+			WillReturnResult(sqlmock.NewResult(10, 3))
+		vals := []*TestAutoModel{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+		res, err := NewInsertSQL[TestAutoModel](db).Values(vals...).ExecuteWithContext(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, res.err)
+		assert.Equal(t, []*TestAutoModel{{Id: 10, Name: "a"}, {Id: 11, Name: "b"}, {Id: 12, Name: "c"}}, vals)
+	}
+	// 获取自增 ID 失败
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+	mock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewErrorResult(errors.New("no id")))
+	res, err := NewInsertSQL[TestAutoModel](db).Values(&TestAutoModel{Name: "a"}).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, errors.New("no id"), res.err)
+	// PostgreSQL 不回填
+	pg, err := OpenDB(mockDB, DBWithDialect(PostgreSQL))
+	assert.NoError(t, err)
+	mock.ExpectExec("INSERT INTO .*").WillReturnResult(sqlmock.NewErrorResult(errors.New("no id")))
+	val := &TestAutoModel{Name: "a"}
+	res, err = NewInsertSQL[TestAutoModel](pg).Values(val).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, res.err)
+	assert.Equal(t, &TestAutoModel{Name: "a"}, val)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// SQLite 的 LastInsertId 是最后一行的 ID
+	sqlite, err := Open("sqlite3", "file:auto_increment.db?cache=shared&mode=memory", DBWithDialect(SQLite3))
+	assert.NoError(t, err)
+	_, err = sqlite.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_auto_model` (`id` INTEGER PRIMARY KEY AUTOINCREMENT, `name` TEXT);")
+	assert.NoError(t, err)
+	first := &TestAutoModel{Name: "first"}
+	_, err = NewInsertSQL[TestAutoModel](sqlite).Values(first).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), first.Id)
+	vals := []*TestAutoModel{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	res, err = NewInsertSQL[TestAutoModel](sqlite).Values(vals...).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, res.err)
+	assert.Equal(t, []*TestAutoModel{{Id: 2, Name: "a"}, {Id: 3, Name: "b"}, {Id: 4, Name: "c"}}, vals)
+	found, err := FindByPKs[TestAutoModel](ctx, sqlite, nil, 2, 3, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, vals, found)
+
+	// 没有指定方言的时候，Open 根据驱动名称选择 SQLite 的方言
+	orm := memoryDBWithDB("auto_increment_default", t)
+	_, err = orm.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_auto_model` (`id` INTEGER PRIMARY KEY AUTOINCREMENT, `name` TEXT);")
+	assert.NoError(t, err)
+	vals = []*TestAutoModel{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	res, err = NewInsertSQL[TestAutoModel](orm).Values(vals...).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, res.err)
+	assert.Equal(t, []*TestAutoModel{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}, {Id: 3, Name: "c"}}, vals)
+	found, err = FindByPKs[TestAutoModel](ctx, orm, nil, 1, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, vals, found)
+}
@@ -6,14 +6,26 @@ import (
 )
 
 var (
-	ErrNotSupportPredicate      = errors.New("不支持Predicate类型")
-	ErrNotSupportModelType      = errors.New("不支持模型类型")
-	ErrNotUpdateSQLSetClause    = errors.New("更新语句没有SET子句")
-	ErrNotInsertSQLValuesClause = errors.New("插入语句没有VALUES子句")
-	ErrNoRows                   = errors.New("没有查询到数据")
-	ErrUnsupportedNil           = errors.New("不支持空指针类型")
-	ErrNoSQL                    = errors.New("SQL语句不能为空")
-	ErrNoFieldName              = errors.New("SQL的列名不能为空")
+	ErrNotSupportPredicate            = errors.New("不支持Predicate类型")
+	ErrNotSupportModelType            = errors.New("不支持模型类型")
+	ErrNotUpdateSQLSetClause          = errors.New("更新语句没有SET子句")
+	ErrNotInsertSQLValuesClause       = errors.New("插入语句没有VALUES子句")
+	ErrNoRows                         = errors.New("没有查询到数据")
+	ErrUnsupportedNil                 = errors.New("不支持空指针类型")
+	ErrNoSQL                          = errors.New("SQL语句不能为空")
+	ErrNoFieldName                    = errors.New("SQL的列名不能为空")
+	ErrNoInValues                     = errors.New("IN 语句的参数不能为空")
+	ErrNotSupportSelectable           = errors.New("不支持的查询列类型")
+	ErrNotSupportTableReference       = errors.New("不支持的表类型")
+	ErrSubqueryNoAlias                = errors.New("FROM 子句中的子查询必须要有别名")
+	ErrOrderByOrLimitInCompoundBranch = errors.New("组合查询的分支中不能使用 ORDER BY、LIMIT 和 OFFSET")
+	ErrNoPrimaryKey                   = errors.New("表模型没有主键")
+	ErrMultipleAutoIncrement          = errors.New("一个表模型只能有一个自增列")
+	ErrMixedAutoIncrementValues       = errors.New("批量插入的时候自增列要么都有值，要么都没有值")
+	ErrUpsertNoConflictFields         = errors.New("DO UPDATE 必须指定冲突的字段")
+	ErrManagerFrozen                  = errors.New("表模型管理器已经冻结，不能再注册表模型")
+	ErrDistinctWithoutAggregate       = errors.New("DISTINCT 只能用在聚合函数中")
+	ErrNamingStrategyWithModels       = errors.New("表模型管理器中已经有表模型了，不能再修改命名策略")
 )
 
 func NewErrNotSupportUnknownField(val any) error {
@@ -23,6 +35,42 @@ func NewErrInvalidTagContext(val string) error {
 	return errors.New(fmt.Sprintf("不支持标签文本 %s ", val))
 }
 
+func NewErrNotSupportEmbedded(fieldName string) error {
+	return errors.New(fmt.Sprintf("字段 %s 不能展开，只支持展开结构体，不支持结构体指针 ", fieldName))
+}
+
+func NewErrDuplicateField(fieldName string) error {
+	return errors.New(fmt.Sprintf("字段名 %s 重复 ", fieldName))
+}
+
+func NewErrDuplicateColumn(column string) error {
+	return errors.New(fmt.Sprintf("列名 %s 重复 ", column))
+}
+
+func NewErrModelNotRegistered(name string) error {
+	return errors.New(fmt.Sprintf("表模型 %s 没有注册 ", name))
+}
+
+func NewErrDuplicateCTE(name string) error {
+	return errors.New(fmt.Sprintf("CTE %s 重复定义 ", name))
+}
+
+func NewErrUnknownTag(key string) error {
+	return errors.New(fmt.Sprintf("不支持未知标签 %s ", key))
+}
+
 func NewErrNotSupportUnknownColumn(val any) error {
 	return errors.New(fmt.Sprintf("不支持未知列名 %v ", val))
 }
+
+func NewErrFailedToRollbackTx(bizErr error, rbErr error, panicked bool) error {
+	return fmt.Errorf("回滚事务失败，业务错误 %w，回滚错误 %s，是否 panic：%t", bizErr, rbErr, panicked)
+}
+
+func NewErrPrimaryKeyValues(want int, got int) error {
+	return errors.New(fmt.Sprintf("主键有 %d 个字段，但是传入了 %d 个值", want, got))
+}
+
+func NewErrInvalidFieldValue(fieldName string, val any) error {
+	return errors.New(fmt.Sprintf("字段 %s 不支持值 %v ", fieldName, val))
+}
@@ -19,16 +19,36 @@ func (r reflectValuer) SetField(rows *sql.Rows) error {
 	if err != nil {
 		return err
 	}
-	receiptFields := make([]any, 0, len(r.model.Fields))                    // 保存Scan需要数据
-	receiptInterfaceFields := make([]reflect.Value, 0, len(r.model.Fields)) // 用于保存每个字段的 Value类型
+	receiptFields := make([]any, 0, len(r.model.Fields)) // 保存Scan需要数据
+	setters := make([]func(), 0, len(orderColumnsStr))   // Scan 之后把数据设置到字段上
 	for _, str := range orderColumnsStr {
-		fd, ok := r.model.ColumnsMap[str]
+		path, ok := r.model.FieldByColumn(str)
 		if !ok {
 			return errs.NewErrNotSupportUnknownColumn(str)
 		}
-		temp := reflect.New(fd.Type)
-		receiptFields = append(receiptFields, temp.Interface())
-		receiptInterfaceFields = append(receiptInterfaceFields, temp.Elem())
+		fd := path[len(path)-1]
+		var dest any
+		var value func() (reflect.Value, bool)
+		if throughPointer(path) {
+			// 经过结构体指针的列是 NULL 的时候不设置，这样嵌套结构体的列都是 NULL 的时候，结构体指针就还是 nil
+			dest, value = nullableDest(fd)
+		} else {
+			temp := reflect.New(fd.Type).Elem()
+			var after func()
+			dest, after = scanDest(fd, temp)
+			value = func() (reflect.Value, bool) {
+				if after != nil {
+					after()
+				}
+				return temp, true
+			}
+		}
+		receiptFields = append(receiptFields, dest)
+		setters = append(setters, func() {
+			if val, ok := value(); ok {
+				r.fieldValue(path).Set(val)
+			}
+		})
 	}
 	// 接收SQL返回的结果数据
 	err = rows.Scan(receiptFields...)
@@ -36,22 +56,53 @@ func (r reflectValuer) SetField(rows *sql.Rows) error {
 		return err
 	}
 	// 将Scan出来的数据设置到 tp 结构体字段上
-	for idx, str := range orderColumnsStr {
-		fd, ok := r.model.ColumnsMap[str]
-		if !ok {
-			return errs.NewErrNotSupportUnknownColumn(str)
-		}
-		r.t.FieldByName(fd.FieldName).Set(receiptInterfaceFields[idx])
+	for _, setter := range setters {
+		setter()
 	}
 	return nil
 }
 
+// fieldValue 获取字段在 T 结构体中的 Value
+// 嵌套结构体需要一层一层地往里面找
+func (r reflectValuer) fieldValue(path []*model.Field) reflect.Value {
+	val := r.t
+	for _, fd := range path[:len(path)-1] {
+		val = nestedValue(val.FieldByIndex(fd.FieldIndex))
+	}
+	return val.FieldByIndex(path[len(path)-1].FieldIndex)
+}
+
+// nestedValue 获取嵌套结构体
+// 结构体指针为 nil 的时候先创建一个新的结构体
+func nestedValue(val reflect.Value) reflect.Value {
+	if val.Kind() != reflect.Pointer {
+		return val
+	}
+	if val.IsNil() {
+		val.Set(reflect.New(val.Type().Elem()))
+	}
+	return val.Elem()
+}
+
 func (r reflectValuer) GetField(fieldName string) (any, error) {
 	fd, ok := r.model.FieldsMap[fieldName]
 	if !ok {
 		return nil, errs.NewErrNotSupportUnknownField(fieldName)
 	}
-	return r.t.FieldByName(fd.FieldName).Interface(), nil
+	return r.t.FieldByIndex(fd.FieldIndex).Interface(), nil
+}
+
+func (r reflectValuer) SetFieldValue(fieldName string, val any) error {
+	fd, ok := r.model.FieldsMap[fieldName]
+	if !ok {
+		return errs.NewErrNotSupportUnknownField(fieldName)
+	}
+	v, err := convertValue(fd, val)
+	if err != nil {
+		return err
+	}
+	r.t.FieldByIndex(fd.FieldIndex).Set(v)
+	return nil
 }
 
 var _ FactoryValuer = NewReflectValuer
@@ -21,16 +21,62 @@ func (u unsafeValuer) SetField(rows *sql.Rows) error {
 		return err
 	}
 	receiptInterfaceFields := make([]any, 0, len(u.model.Fields))
+	afters := make([]func(), 0, len(orderColumnsStr))
 	for _, str := range orderColumnsStr {
-		fd, ok := u.model.ColumnsMap[str]
+		path, ok := u.model.FieldByColumn(str)
 		if !ok {
 			return errs.NewErrNotSupportUnknownColumn(str)
 		}
-		// 计算当前字段在 T 结构体中的偏移量
-		address := unsafe.Pointer(uintptr(u.addr) + fd.Offset)
-		receiptInterfaceFields = append(receiptInterfaceFields, reflect.NewAt(fd.Type, address).Interface())
+		fd := path[len(path)-1]
+		// 经过结构体指针的列先 Scan 到临时变量中，不是 NULL 的时候才创建结构体并且设置字段
+		// 这样嵌套结构体的列都是 NULL 的时候，结构体指针就还是 nil
+		if throughPointer(path) {
+			dest, value := nullableDest(fd)
+			receiptInterfaceFields = append(receiptInterfaceFields, dest)
+			afters = append(afters, func() {
+				if val, ok := value(); ok {
+					reflect.NewAt(fd.Type, u.fieldAddress(path)).Elem().Set(val)
+				}
+			})
+			continue
+		}
+		dest, after := scanDest(fd, reflect.NewAt(fd.Type, u.fieldAddress(path)).Elem())
+		receiptInterfaceFields = append(receiptInterfaceFields, dest)
+		if after != nil {
+			afters = append(afters, after)
+		}
+	}
+	if err = rows.Scan(receiptInterfaceFields...); err != nil {
+		return err
+	}
+	for _, after := range afters {
+		after()
 	}
-	return rows.Scan(receiptInterfaceFields...)
+	return nil
+}
+
+// fieldAddress 计算字段在 T 结构体中的地址
+// 嵌套结构体需要一层一层地往里面算
+func (u unsafeValuer) fieldAddress(path []*model.Field) unsafe.Pointer {
+	address := u.addr
+	for _, fd := range path[:len(path)-1] {
+		address = nestedAddress(unsafe.Pointer(uintptr(address)+fd.Offset), fd.Type)
+	}
+	return unsafe.Pointer(uintptr(address) + path[len(path)-1].Offset)
+}
+
+// nestedAddress 计算嵌套结构体的起始地址
+// 结构体字段本身的地址就是嵌套结构体的起始地址
+// 结构体指针字段需要取出指针指向的地址，指针为 nil 的时候先创建一个新的结构体
+func nestedAddress(address unsafe.Pointer, typ reflect.Type) unsafe.Pointer {
+	if typ.Kind() != reflect.Pointer {
+		return address
+	}
+	ptr := reflect.NewAt(typ, address).Elem()
+	if ptr.IsNil() {
+		ptr.Set(reflect.New(typ.Elem()))
+	}
+	return unsafe.Pointer(ptr.Pointer())
 }
 
 func (u unsafeValuer) GetField(fieldName string) (any, error) {
@@ -43,6 +89,20 @@ func (u unsafeValuer) GetField(fieldName string) (any, error) {
 	return reflect.NewAt(fd.Type, address).Elem().Interface(), nil
 }
 
+func (u unsafeValuer) SetFieldValue(fieldName string, val any) error {
+	fd, ok := u.model.FieldsMap[fieldName]
+	if !ok {
+		return errs.NewErrNotSupportUnknownField(fieldName)
+	}
+	v, err := convertValue(fd, val)
+	if err != nil {
+		return err
+	}
+	address := unsafe.Pointer(uintptr(u.addr) + fd.Offset)
+	reflect.NewAt(fd.Type, address).Elem().Set(v)
+	return nil
+}
+
 var _ FactoryValuer = NewUnsafeValuer
 
 // NewUnsafeValuer entity必须是一个一级指针
@@ -2,7 +2,9 @@ package valuer
 
 import (
 	"database/sql"
+	"github.com/borntodie-new/orm-framework/internal/errs"
 	"github.com/borntodie-new/orm-framework/model"
+	"reflect"
 )
 
 type Valuer interface {
@@ -10,7 +12,61 @@ type Valuer interface {
 	SetField(rows *sql.Rows) error
 	// GetField 将 Go 中的结构体上的字段数据返回
 	GetField(fieldName string) (any, error)
+	// SetFieldValue 设置 Go 中结构体上的字段数据，val 的类型可以转换成字段的类型就行
+	// 比如插入之后把 int64 类型的自增 ID 回填到 int8 类型的字段上
+	SetFieldValue(fieldName string, val any) error
 }
 
 // FactoryValuer 一个简单的工厂，用于返回 Valuer 类型的实现
 type FactoryValuer func(model *model.Model, entity any) Valuer
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// scanDest 获取字段 Scan 的目标，field 必须是可以寻址的
+// 字段开启了 NullAsZero 的时候，先 Scan 到 **T 中，Scan 之后再通过 after 设置到字段上，这样 NULL 就会变成零值
+// 指针字段和实现了 sql.Scanner 的字段本身就能处理 NULL，直接 Scan 到字段上，after 为 nil
+func scanDest(fd *model.Field, field reflect.Value) (dest any, after func()) {
+	if !fd.NullAsZero || fd.Type.Kind() == reflect.Pointer || reflect.PointerTo(fd.Type).Implements(scannerType) {
+		return field.Addr().Interface(), nil
+	}
+	ptr := reflect.New(reflect.PointerTo(fd.Type))
+	return ptr.Interface(), func() {
+		if ptr.Elem().IsNil() {
+			field.SetZero()
+			return
+		}
+		field.Set(ptr.Elem().Elem())
+	}
+}
+
+// throughPointer 嵌套结构体的路径中是否有结构体指针
+// 比如 LEFT JOIN 没有匹配到数据的时候，嵌套结构体的列都是 NULL，这个时候结构体指针应该是 nil
+func throughPointer(path []*model.Field) bool {
+	for _, fd := range path[:len(path)-1] {
+		if fd.Type.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
+
+// nullableDest 获取能够接收 NULL 的 Scan 目标 **T
+// Scan 之后通过 value 获取字段的值，列是 NULL 的时候 ok 为 false
+func nullableDest(fd *model.Field) (dest any, value func() (val reflect.Value, ok bool)) {
+	ptr := reflect.New(reflect.PointerTo(fd.Type))
+	return ptr.Interface(), func() (reflect.Value, bool) {
+		if ptr.Elem().IsNil() {
+			return reflect.Value{}, false
+		}
+		return ptr.Elem().Elem(), true
+	}
+}
+
+// convertValue 把 val 转换成字段的类型
+func convertValue(fd *model.Field, val any) (reflect.Value, error) {
+	v := reflect.ValueOf(val)
+	if !v.IsValid() || !v.Type().ConvertibleTo(fd.Type) {
+		return reflect.Value{}, errs.NewErrInvalidFieldValue(fd.FieldName, val)
+	}
+	return v.Convert(fd.Type), nil
+}
@@ -0,0 +1,111 @@
+package orm_framework
+
+// 算术表达式
+// Go中的使用：NewUpdateSQL[Item](db).Values("Stock", F("Stock").Sub(1))
+// SQL中的使用：UPDATE `item` SET `stock` = `stock` - ?;
+
+type mathOp string
+
+func (m mathOp) String() string {
+	return string(m)
+}
+
+const (
+	ADDType mathOp = " + "
+	SUBType mathOp = " - "
+	MULType mathOp = " * "
+	DIVType mathOp = " / "
+)
+
+// precedence 运算符的优先级，数字越大优先级越高
+func (m mathOp) precedence() int {
+	switch m {
+	case MULType, DIVType:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// MathExpr 算术表达式
+// 需要实现 Expression 接口，因为算术表达式既可以作为 Predicate 的 left 或 right，也可以作为 UPDATE 语句中的值
+type MathExpr struct {
+	left  Expression
+	op    mathOp
+	right Expression
+}
+
+// expr 标记位
+func (m MathExpr) expr() {}
+
+func (m MathExpr) Add(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    ADDType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    SUBType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) Mul(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    MULType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) Div(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    DIVType,
+		right: exprOf(val),
+	}
+}
+
+func (m MathExpr) EQ(val any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    EQType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) NEQ(val any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    NEQType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) GT(val any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    GTType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) GTE(val any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    GTEType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) LT(val any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    LTType,
+		right: exprOf(val),
+	}
+}
+func (m MathExpr) LTE(val any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    LTEType,
+		right: exprOf(val),
+	}
+}
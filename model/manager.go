@@ -1,11 +1,15 @@
 package model
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"github.com/borntodie-new/orm-framework/internal/errs"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
-	"unicode"
+	"sync/atomic"
+	"time"
 )
 
 // 统一管理model表模型的结构
@@ -15,6 +19,58 @@ type Manager struct {
 	// models 需要管理的所有model模型
 	// 为什么用 sync.Map 结构呢？因为这个可以避免并发问题
 	models sync.Map
+	// results 只用于接收查询结果的结构体的模型，比如投影查询中的 DTO
+	// 和表模型分开保存，这样冻结之后也能解析，又不会被当成表模型使用
+	results sync.Map
+	// frozen 冻结之后不能再注册新的表模型
+	frozen atomic.Bool
+	// naming 命名策略，为 nil 的时候使用 SnakeCase
+	naming NamingStrategy
+}
+
+// ManagerOption Manager 的可选配置
+type ManagerOption func(m *Manager)
+
+// NewManager 创建表模型管理器，直接使用 &Manager{} 也是可以的
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ManagerWithNamingStrategy 指定命名策略
+func ManagerWithNamingStrategy(naming NamingStrategy) ManagerOption {
+	return func(m *Manager) {
+		m.naming = naming
+	}
+}
+
+// SetNamingStrategy 修改命名策略
+// 已经有表模型的时候不能修改，否则已经解析的表模型和之后解析的表模型用的就是不同的命名策略了
+func (m *Manager) SetNamingStrategy(naming NamingStrategy) error {
+	if m.frozen.Load() {
+		return errs.ErrManagerFrozen
+	}
+	hasModels := false
+	m.models.Range(func(key, value any) bool {
+		hasModels = true
+		return false
+	})
+	if hasModels {
+		return errs.ErrNamingStrategyWithModels
+	}
+	m.naming = naming
+	return nil
+}
+
+// namingStrategy 获取命名策略
+func (m *Manager) namingStrategy() NamingStrategy {
+	if m.naming == nil {
+		return SnakeCase
+	}
+	return m.naming
 }
 
 // Get 获取表模型
@@ -35,57 +91,221 @@ func (m *Manager) Get(key any) (*Model, error) {
 	if ok {
 		return mod.(*Model), nil
 	}
+	// 冻结之后所有的表模型都必须提前注册好
+	if m.frozen.Load() {
+		return nil, errs.NewErrModelNotRegistered(typ.Name())
+	}
 	return m.register(key)
 }
 
+// GetResult 获取只用于接收查询结果的结构体的模型，比如投影查询中的 DTO
+// 和 Get 不同的是，冻结之后也会自动解析没有注册过的结构体，DTO 不需要提前注册
+// 注册过的表模型优先，这样 column 标签之外的配置，比如 WithColumnName，也能生效
+func (m *Manager) GetResult(key any) (*Model, error) {
+	if key == nil {
+		return nil, errs.ErrUnsupportedNil
+	}
+	typ := reflect.TypeOf(key)
+	if typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
+		return nil, errs.ErrNotSupportModelType
+	}
+	typ = typ.Elem()
+	if mod, ok := m.models.Load(typ); ok {
+		return mod.(*Model), nil
+	}
+	if mod, ok := m.results.Load(typ); ok {
+		return mod.(*Model), nil
+	}
+	mod, err := m.parseModel(typ, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	m.results.Store(typ, mod)
+	return mod, nil
+}
+
+// Freeze 冻结 Manager，一般在启动的时候注册完所有的表模型之后调用
+// 冻结之后不能再调用 Register，Get 也不会再自动解析没有注册过的表模型，而是直接返回错误
+func (m *Manager) Freeze() {
+	m.frozen.Store(true)
+}
+
 func (m *Manager) register(key any) (*Model, error) {
 	// 因为在 Get 方法中已经做了判断，所以这里直接用就好
-	typ := reflect.TypeOf(key).Elem()
+	return m.registerType(reflect.TypeOf(key).Elem(), map[reflect.Type]bool{})
+}
+
+// registerType 解析并且保存 typ 对应的表模型
+// visiting 是正在解析的结构体类型，用于处理 type Node struct { Parent *Node } 这种自己引用自己的情况
+func (m *Manager) registerType(typ reflect.Type, visiting map[reflect.Type]bool) (*Model, error) {
+	mod, err := m.parseModel(typ, visiting)
+	if err != nil {
+		return nil, err
+	}
+	m.models.Store(typ, mod)
+	return mod, nil
+}
+
+// parseModel 解析 typ 对应的表模型，但是不保存
+func (m *Manager) parseModel(typ reflect.Type, visiting map[reflect.Type]bool) (*Model, error) {
+	visiting[typ] = true
+	defer delete(visiting, typ)
 	// 构建数据
 	numField := typ.NumField()
-	fieldsMap := make(map[string]*Field, numField)
-	columnsMap := make(map[string]*Field, numField)
-	fields := make([]*Field, 0, numField)
-	for i := 0; i < numField; i++ {
+	mod := &Model{
+		FieldsMap:  make(map[string]*Field, numField),
+		ColumnsMap: make(map[string]*Field, numField),
+		Fields:     make([]*Field, 0, numField),
+	}
+	if err := m.parseFields(mod, typ, embeddedScope{}, visiting); err != nil {
+		return nil, err
+	}
+	// 没有声明主键的时候，按照约定 Id 字段就是主键
+	if id, ok := mod.FieldsMap["Id"]; ok && len(mod.PrimaryKeys) <= 0 {
+		id.PrimaryKey = true
+		mod.PrimaryKeys = append(mod.PrimaryKeys, id)
+	}
+	// 注意：这里的 TableName 接口不能定义在 ORM 框架的那个包中，因为会出现 循环引入 的问题
+	tbn, ok := reflect.New(typ).Interface().(TableName)
+	if ok {
+		mod.TableName = tbn.TableName()
+	}
+	if mod.TableName == "" {
+		mod.TableName = m.namingStrategy().TableName(typ.Name())
+	}
+	return mod, nil
+}
+
+// embeddedScope 展开的结构体在外层结构体中的位置
+// 匿名结构体和带 embedded 标签的结构体字段会被展开，它们的字段就像是直接定义在外层结构体中一样
+type embeddedScope struct {
+	// index 展开的结构体在外层结构体中的字段下标路径
+	index []int
+	// offset 展开的结构体相对于外层结构体起始位置的偏移量
+	offset uintptr
+	// fieldPrefix 字段名的前缀，带 embedded 标签的结构体字段是 Addr. 这种形式，匿名结构体的字段名不变
+	fieldPrefix string
+	// columnPrefix 列名的前缀，通过 prefix 标签指定
+	columnPrefix string
+}
+
+// parseFields 解析 typ 的字段并且添加到 mod 中
+// 展开的结构体会递归调用这个方法，所以 Offset 和 Index 都是相对于最外层的结构体的
+func (m *Manager) parseFields(mod *Model, typ reflect.Type, scope embeddedScope, visiting map[reflect.Type]bool) error {
+	for i := 0; i < typ.NumField(); i++ {
 		fd := typ.Field(i)
+		// 忽略的字段不参与映射
+		if fd.Tag.Get(FieldTagName) == IgnoreTagName {
+			continue
+		}
 		tagsMap, err := m.parseTag(fd.Tag)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		index := make([]int, len(scope.index), len(scope.index)+1)
+		copy(index, scope.index)
+		index = append(index, i)
+		_, embedded := tagsMap[EmbeddedTagName]
+		if embedded || (fd.Anonymous && isPlainStruct(fd.Type)) {
+			// 结构体指针没办法通过偏移量计算出字段的地址，所以只支持结构体
+			if fd.Type.Kind() != reflect.Struct || !isPlainStruct(fd.Type) {
+				return errs.NewErrNotSupportEmbedded(scope.fieldPrefix + fd.Name)
+			}
+			sub := embeddedScope{
+				index:        index,
+				offset:       scope.offset + fd.Offset,
+				fieldPrefix:  scope.fieldPrefix,
+				columnPrefix: scope.columnPrefix + tagsMap[PrefixTagName],
+			}
+			// 匿名结构体的字段会被提升，Go 中可以直接访问，所以字段名不需要前缀
+			if !fd.Anonymous {
+				sub.fieldPrefix += fd.Name + "."
+			}
+			if err = m.parseFields(mod, fd.Type, sub, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+		// prefix 只能用在展开的结构体上
+		if _, ok := tagsMap[PrefixTagName]; ok {
+			return errs.NewErrInvalidTagContext(PrefixTagName + "=" + tagsMap[PrefixTagName])
 		}
 		f := &Field{
-			FieldName: fd.Name,
-			Type:      fd.Type,
-			Offset:    fd.Offset,
+			FieldName:  scope.fieldPrefix + fd.Name,
+			Type:       fd.Type,
+			Offset:     scope.offset + fd.Offset,
+			FieldIndex: index,
 		}
 		colName, ok := tagsMap[ColumnTagName]
 		if ok && colName != "" {
-			f.ColumnName = colName
+			f.ColumnName = scope.columnPrefix + colName
 		} else {
-			f.ColumnName = underscoreName(fd.Name)
+			f.ColumnName = scope.columnPrefix + m.namingStrategy().ColumnName(fd.Name)
+		}
+		if err = f.setAttributes(tagsMap); err != nil {
+			return err
+		}
+		// 展开之后字段名或者列名重复了，就没办法知道应该映射到哪个字段上
+		if _, ok = mod.FieldsMap[f.FieldName]; ok {
+			return errs.NewErrDuplicateField(f.FieldName)
+		}
+		if _, ok = mod.ColumnsMap[f.ColumnName]; ok {
+			return errs.NewErrDuplicateColumn(f.ColumnName)
+		}
+		if f.PrimaryKey {
+			mod.PrimaryKeys = append(mod.PrimaryKeys, f)
+		}
+		if f.AutoIncrement {
+			if mod.AutoIncrement != nil {
+				return errs.ErrMultipleAutoIncrement
+			}
+			mod.AutoIncrement = f
+		}
+		// 结构体字段需要解析出它自己的模型，用于映射 JOIN 查询的嵌套结果
+		if f.SubModel, err = m.subModel(fd.Type, visiting); err != nil {
+			return err
 		}
 
-		fieldsMap[fd.Name] = f
-		// columnsMap[underscoreName(fd.Name)] = f
-		columnsMap[f.ColumnName] = f
-		fields = append(fields, f)
+		mod.FieldsMap[f.FieldName] = f
+		mod.ColumnsMap[f.ColumnName] = f
+		mod.Fields = append(mod.Fields, f)
 	}
-	// 注意：这里的 TableName 接口不能定义在 ORM 框架的那个包中，因为会出现 循环引入 的问题
-	var tableName string
-	tbn, ok := key.(TableName)
-	if ok {
-		tableName = tbn.TableName()
+	return nil
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// isPlainStruct 是否是普通的结构体（或者结构体指针）
+// 能够被 database/sql 直接处理的结构体，比如 sql.NullString、time.Time，不算普通的结构体
+func isPlainStruct(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && typ != timeType &&
+		!reflect.PointerTo(typ).Implements(scannerType) && !typ.Implements(valuerType)
+}
+
+// subModel 获取结构体字段的模型，不是嵌套结构体的时候返回 nil
+func (m *Manager) subModel(typ reflect.Type, visiting map[reflect.Type]bool) (*Model, error) {
+	if !isPlainStruct(typ) {
+		return nil, nil
 	}
-	if tableName == "" {
-		tableName = underscoreName(typ.Name())
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
 	}
-	mod := &Model{
-		TableName:  tableName,
-		FieldsMap:  fieldsMap,
-		ColumnsMap: columnsMap,
-		Fields:     fields,
+	// 自己引用自己的结构体不再往下解析，否则会无限递归
+	if visiting[typ] {
+		return nil, nil
 	}
-	m.models.Store(typ, mod)
-	return mod, nil
+	mod, ok := m.models.Load(typ)
+	if ok {
+		return mod.(*Model), nil
+	}
+	return m.registerType(typ, visiting)
 }
 
 // Register 注册表模型
@@ -133,36 +353,100 @@ func (m *Manager) register(key any) (*Model, error) {
 //	return mod, nil
 //}
 
+// tagHasValue 支持的标签，值表示这个标签是不是键值标签
+var tagHasValue = map[string]bool{
+	ColumnTagName:        true,
+	PrimaryKeyTagName:    false,
+	AutoIncrementTagName: false,
+	ReadonlyTagName:      false,
+	DefaultTagName:       true,
+	SizeTagName:          true,
+	TypeTagName:          true,
+	NullableTagName:      false,
+	UniqueTagName:        false,
+	IndexTagName:         true,
+	EmbeddedTagName:      false,
+	PrefixTagName:        true,
+	NullAsZeroTagName:    false,
+}
+
 func (m *Manager) parseTag(tag reflect.StructTag) (map[string]string, error) {
 	res := make(map[string]string, 1)
 	tagStr, ok := tag.Lookup(FieldTagName)
 	if !ok {
 		return res, nil
 	}
-	pairs := strings.Split(tagStr, ",")
+	pairs, err := splitTag(tagStr)
+	if err != nil {
+		return nil, err
+	}
 	for _, pair := range pairs {
-		temp := strings.Split(pair, "=")
-		if len(temp) != 2 {
+		// 只按照第一个 = 分隔，值中可以有 =，比如 default=a=b
+		key, val, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		hasValue, ok := tagHasValue[key]
+		if !ok {
+			return nil, errs.NewErrUnknownTag(key)
+		}
+		switch {
+		case !found && !hasValue:
+			// 开关标签，比如 pk
+			res[key] = ""
+		case found && hasValue:
+			res[key] = strings.TrimSpace(val)
+		default:
 			return nil, errs.NewErrInvalidTagContext(pair)
 		}
-		res[temp[0]] = temp[1]
 	}
 	return res, nil
 }
 
-// underscoreName 驼峰转字符串命名
-func underscoreName(tableName string) string {
-	var buf []byte
-	for i, v := range tableName {
-		if unicode.IsUpper(v) {
-			if i != 0 {
-				buf = append(buf, '_')
+// splitTag 按照逗号把标签分隔成多个标签
+// 括号和单引号中的逗号不分隔，比如 type=decimal(10,2)、type=enum('a','b')、default='a,b'
+func splitTag(tagStr string) ([]string, error) {
+	var pairs []string
+	depth, quoted, start := 0, false, 0
+	for i, r := range tagStr {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return nil, errs.NewErrInvalidTagContext(tagStr)
 			}
-			buf = append(buf, byte(unicode.ToLower(v)))
-		} else {
-			buf = append(buf, byte(v))
+		case r == ',' && depth == 0:
+			pairs = append(pairs, tagStr[start:i])
+			start = i + 1
 		}
+	}
+	// 括号或者引号没有闭合
+	if depth != 0 || quoted {
+		return nil, errs.NewErrInvalidTagContext(tagStr)
+	}
+	return append(pairs, tagStr[start:]), nil
+}
 
+// setAttributes 根据标签设置字段的属性
+func (f *Field) setAttributes(tagsMap map[string]string) error {
+	_, f.PrimaryKey = tagsMap[PrimaryKeyTagName]
+	_, f.AutoIncrement = tagsMap[AutoIncrementTagName]
+	_, f.Readonly = tagsMap[ReadonlyTagName]
+	_, f.Nullable = tagsMap[NullableTagName]
+	_, f.Unique = tagsMap[UniqueTagName]
+	_, f.NullAsZero = tagsMap[NullAsZeroTagName]
+	f.Default, f.HasDefault = tagsMap[DefaultTagName]
+	f.SQLType = tagsMap[TypeTagName]
+	f.Index = tagsMap[IndexTagName]
+	if size, ok := tagsMap[SizeTagName]; ok {
+		val, err := strconv.Atoi(size)
+		if err != nil || val <= 0 {
+			return errs.NewErrInvalidTagContext(SizeTagName + "=" + size)
+		}
+		f.Size = val
 	}
-	return string(buf)
+	return nil
 }
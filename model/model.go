@@ -2,11 +2,52 @@ package model
 
 import (
 	"reflect"
+	"strings"
 )
 
+// 标签的语法
+// 多个标签之间用逗号分隔，标签分为两种：
+// 1. 开关标签，不能有值，比如 pk、auto_increment、readonly、nullable、unique
+// 2. 键值标签，必须有值，比如 column=user_name、default=0、size=255、type=varchar(255)、index=idx_name
+// 整个标签是 - 的时候，表示忽略这个字段，比如 `orm:"-"`
+// 值中的逗号放在括号或者单引号中就可以了，比如 type=decimal(10,2)、type=enum('a','b')、default='a,b'
+// 注意：未知的标签在注册模型的时候就会报错
+// Go中的使用：Name string `orm:"column=user_name,size=64,unique,index=idx_name"`
 const (
 	FieldTagName  = "orm"
 	ColumnTagName = "column"
+	// PrimaryKeyTagName 主键标签，多个字段都有这个标签的时候就是联合主键
+	PrimaryKeyTagName = "pk"
+	// AutoIncrementTagName 自增列标签，插入的时候值为零就不插入这一列，插入之后把生成的 ID 回填到结构体中
+	AutoIncrementTagName = "auto_increment"
+	// IgnoreTagName 忽略字段的标签，只能单独使用
+	IgnoreTagName = "-"
+	// ReadonlyTagName 只读列标签，比如由数据库生成的列，插入的时候默认不会插入这一列
+	ReadonlyTagName = "readonly"
+	// DefaultTagName 列的默认值
+	DefaultTagName = "default"
+	// SizeTagName 列的长度，必须是正整数
+	SizeTagName = "size"
+	// TypeTagName 列在数据库中的类型
+	TypeTagName = "type"
+	// NullableTagName 列可以为 NULL
+	NullableTagName = "nullable"
+	// UniqueTagName 列有唯一约束
+	UniqueTagName = "unique"
+	// IndexTagName 列所在的索引名，多个字段的索引名相同的时候就是联合索引
+	IndexTagName = "index"
+	// EmbeddedTagName 展开结构体字段的标签，结构体的字段会被当作外层结构体的字段，字段名是 Addr.City 这种形式
+	// 匿名结构体默认就会展开，不需要这个标签
+	EmbeddedTagName = "embedded"
+	// NullAsZeroTagName 查询结果是 NULL 的时候设置成零值，而不是返回错误
+	// 指针字段和 sql.NullString 这种实现了 sql.Scanner 的字段本身就能处理 NULL，不需要这个标签
+	NullAsZeroTagName = "null_as_zero"
+	// PrefixTagName 展开的结构体的列名前缀，只能用在展开的结构体上
+	// Go中的使用：Addr Address `orm:"embedded,prefix=addr_"`，Addr.City 字段对应的列名就是 addr_city
+	PrefixTagName = "prefix"
+	// NestedSeparator 嵌套结构体的列名分隔符
+	// 例如 u__name 表示列名为 u 的结构体字段中，列名为 name 的字段
+	NestedSeparator = "__"
 )
 
 // 存储表模型
@@ -25,6 +66,11 @@ type Model struct {
 	ColumnsMap map[string]*Field
 	// Fields Go中结构体的字段的切片
 	Fields []*Field
+	// PrimaryKeys 主键字段，按照结构体中字段的顺序
+	// 没有字段带 pk 标签的时候，Id 字段就是主键
+	PrimaryKeys []*Field
+	// AutoIncrement 自增列，一个表最多只有一个
+	AutoIncrement *Field
 }
 
 // Field Go中字段元数据
@@ -36,8 +82,37 @@ type Field struct {
 	// Type 字段在Go中的类型
 	Type reflect.Type
 	// Offset 当前字段在当前结构体中的相对位置偏移量
-	// 相对于 T 结构体的起始位置
+	// 相对于 T 结构体的起始位置，展开的结构体中的字段也是
 	Offset uintptr
+	// FieldIndex 字段在 T 结构体中的下标路径，用于 reflect.Value.FieldByIndex
+	// 展开的结构体中的字段有多个下标，比如 [0 1] 表示第 0 个字段中的第 1 个字段
+	FieldIndex []int
+	// SubModel 字段是结构体（或者结构体指针）的时候，这个结构体的表模型
+	// JOIN 查询的时候，u__name 这种列就是通过它映射到嵌套的结构体上的
+	// 实现了 sql.Scanner 的结构体，比如 sql.NullString，以及 time.Time 都当作普通字段处理，SubModel 为空
+	SubModel *Model
+	// PrimaryKey 是否是主键
+	PrimaryKey bool
+	// AutoIncrement 是否是自增列
+	AutoIncrement bool
+	// Readonly 是否是只读列
+	Readonly bool
+	// Default 列的默认值，HasDefault 为 false 的时候没有意义
+	Default string
+	// HasDefault 是否声明了默认值，用于区分没有默认值和默认值是空字符串
+	HasDefault bool
+	// Size 列的长度，0 表示没有声明
+	Size int
+	// SQLType 列在数据库中的类型，空字符串表示没有声明
+	SQLType string
+	// Nullable 列是否可以为 NULL
+	Nullable bool
+	// Unique 列是否有唯一约束
+	Unique bool
+	// Index 列所在的索引名，空字符串表示没有索引
+	Index string
+	// NullAsZero 查询结果是 NULL 的时候是否设置成零值
+	NullAsZero bool
 }
 
 // TableName 显性为模型定义表名
@@ -46,3 +121,26 @@ type Field struct {
 type TableName interface {
 	TableName() string
 }
+
+// FieldByColumn 根据列名查找字段
+// 普通的列名直接返回对应的字段
+// 嵌套结构体的列名，比如 u__name，返回的是从外到内的字段路径 [User, Name]
+func (m *Model) FieldByColumn(column string) ([]*Field, bool) {
+	fd, ok := m.ColumnsMap[column]
+	if ok {
+		return []*Field{fd}, true
+	}
+	prefix, rest, found := strings.Cut(column, NestedSeparator)
+	if !found {
+		return nil, false
+	}
+	fd, ok = m.ColumnsMap[prefix]
+	if !ok || fd.SubModel == nil {
+		return nil, false
+	}
+	path, ok := fd.SubModel.FieldByColumn(rest)
+	if !ok {
+		return nil, false
+	}
+	return append([]*Field{fd}, path...), true
+}
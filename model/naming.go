@@ -0,0 +1,111 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy 命名策略，Go 中的名字怎么转换成表名和列名
+// 标签、TableName 接口和 Register 的配置中显式指定的名字不会经过命名策略
+type NamingStrategy interface {
+	// TableName 结构体名转换成表名
+	TableName(structName string) string
+	// ColumnName 字段名转换成列名
+	ColumnName(fieldName string) string
+}
+
+var (
+	// SnakeCase 驼峰转下划线，默认的命名策略
+	// 连续的大写字母当作一个单词，比如 UserID 转换成 user_id，HTTPServer 转换成 http_server
+	SnakeCase NamingStrategy = snakeCaseNaming{}
+	// Identity 不做任何转换，表名和列名就是 Go 中的名字
+	Identity NamingStrategy = identityNaming{}
+)
+
+type snakeCaseNaming struct {
+}
+
+func (s snakeCaseNaming) TableName(structName string) string {
+	return underscoreName(structName)
+}
+
+func (s snakeCaseNaming) ColumnName(fieldName string) string {
+	return underscoreName(fieldName)
+}
+
+type identityNaming struct {
+}
+
+func (i identityNaming) TableName(structName string) string {
+	return structName
+}
+
+func (i identityNaming) ColumnName(fieldName string) string {
+	return fieldName
+}
+
+// TablePrefix 在 naming 的基础上给表名加上前缀，列名不变
+// Go中的使用：TablePrefix("tbl_", SnakeCase)，UserInfo 对应的表名就是 tbl_user_info
+func TablePrefix(prefix string, naming NamingStrategy) NamingStrategy {
+	return tablePrefixNaming{NamingStrategy: naming, prefix: prefix}
+}
+
+type tablePrefixNaming struct {
+	NamingStrategy
+	prefix string
+}
+
+func (t tablePrefixNaming) TableName(structName string) string {
+	return t.prefix + t.NamingStrategy.TableName(structName)
+}
+
+// PluralTable 在 naming 的基础上把表名转换成英文的复数形式，列名不变
+// Go中的使用：PluralTable(SnakeCase)，UserCategory 对应的表名就是 user_categories
+func PluralTable(naming NamingStrategy) NamingStrategy {
+	return pluralNaming{NamingStrategy: naming}
+}
+
+type pluralNaming struct {
+	NamingStrategy
+}
+
+func (p pluralNaming) TableName(structName string) string {
+	return plural(p.NamingStrategy.TableName(structName))
+}
+
+// plural 英文单词的复数形式，只处理常见的规则
+func plural(word string) string {
+	lower := strings.ToLower(word)
+	for _, suffix := range []string{"s", "x", "z", "ch", "sh"} {
+		if strings.HasSuffix(lower, suffix) {
+			return word + "es"
+		}
+	}
+	// 辅音字母加 y 结尾的，把 y 变成 ies
+	if n := len(lower); n > 1 && lower[n-1] == 'y' && !strings.ContainsRune("aeiou", rune(lower[n-2])) {
+		return word[:n-1] + "ies"
+	}
+	return word + "s"
+}
+
+// underscoreName 驼峰转下划线命名
+// 一个大写字母前面是小写字母或者数字，或者它是连续大写字母中的最后一个并且后面是小写字母的时候，就是一个新单词的开始
+func underscoreName(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			sb.WriteRune(r)
+			continue
+		}
+		if i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
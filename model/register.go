@@ -0,0 +1,135 @@
+package model
+
+import (
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"reflect"
+)
+
+// ModelOption 注册表模型的可选配置
+// 用于没办法加标签的结构体，比如第三方库中的结构体，在代码中配置表模型
+// 配置是在解析完结构体之后按照顺序执行的，所以会覆盖标签的配置
+type ModelOption func(m *Model) error
+
+// Register 注册表模型，已经注册过的会被覆盖
+// 和 Get 不一样，注册的时候就会校验所有的配置，有错误会立刻返回，而不是等到第一次构建 SQL 的时候
+// Go中的使用：model.Register[User](manager, WithTableName("tbl_user"), WithColumnName("Name", "user_name"))
+func Register[T any](m *Manager, opts ...ModelOption) (*Model, error) {
+	if m.frozen.Load() {
+		return nil, errs.ErrManagerFrozen
+	}
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, errs.ErrNotSupportModelType
+	}
+	mod, err := m.parseModel(typ, map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		if err = opt(mod); err != nil {
+			return nil, err
+		}
+	}
+	m.models.Store(typ, mod)
+	return mod, nil
+}
+
+// WithTableName 指定表名
+func WithTableName(tableName string) ModelOption {
+	return func(m *Model) error {
+		m.TableName = tableName
+		return nil
+	}
+}
+
+// WithColumnName 指定字段对应的列名，注意这里是 Go 中的字段名
+func WithColumnName(fieldName string, column string) ModelOption {
+	return func(m *Model) error {
+		fd, ok := m.FieldsMap[fieldName]
+		if !ok {
+			return errs.NewErrNotSupportUnknownField(fieldName)
+		}
+		if other, ok := m.ColumnsMap[column]; ok && other != fd {
+			return errs.NewErrDuplicateColumn(column)
+		}
+		delete(m.ColumnsMap, fd.ColumnName)
+		fd.ColumnName = column
+		m.ColumnsMap[column] = fd
+		return nil
+	}
+}
+
+// WithPrimaryKey 指定主键，多个字段就是联合主键
+// 会覆盖标签和 Id 字段的约定
+func WithPrimaryKey(fieldNames ...string) ModelOption {
+	return func(m *Model) error {
+		primaryKeys := make([]*Field, 0, len(fieldNames))
+		for _, fieldName := range fieldNames {
+			fd, ok := m.FieldsMap[fieldName]
+			if !ok {
+				return errs.NewErrNotSupportUnknownField(fieldName)
+			}
+			primaryKeys = append(primaryKeys, fd)
+		}
+		for _, fd := range m.PrimaryKeys {
+			fd.PrimaryKey = false
+		}
+		for _, fd := range primaryKeys {
+			fd.PrimaryKey = true
+		}
+		m.PrimaryKeys = primaryKeys
+		return nil
+	}
+}
+
+// WithNullAsZero 查询结果是 NULL 的时候设置成零值，作用和 null_as_zero 标签一样
+// 没有指定字段的时候，所有的字段都会生效
+func WithNullAsZero(fieldNames ...string) ModelOption {
+	return func(m *Model) error {
+		if len(fieldNames) == 0 {
+			for _, fd := range m.Fields {
+				fd.NullAsZero = true
+			}
+			return nil
+		}
+		for _, fieldName := range fieldNames {
+			fd, ok := m.FieldsMap[fieldName]
+			if !ok {
+				return errs.NewErrNotSupportUnknownField(fieldName)
+			}
+			fd.NullAsZero = true
+		}
+		return nil
+	}
+}
+
+// IgnoreField 忽略字段，作用和 `orm:"-"` 标签一样
+func IgnoreField(fieldNames ...string) ModelOption {
+	return func(m *Model) error {
+		for _, fieldName := range fieldNames {
+			fd, ok := m.FieldsMap[fieldName]
+			if !ok {
+				return errs.NewErrNotSupportUnknownField(fieldName)
+			}
+			delete(m.FieldsMap, fieldName)
+			delete(m.ColumnsMap, fd.ColumnName)
+			m.Fields = removeField(m.Fields, fd)
+			m.PrimaryKeys = removeField(m.PrimaryKeys, fd)
+			if m.AutoIncrement == fd {
+				m.AutoIncrement = nil
+			}
+		}
+		return nil
+	}
+}
+
+// removeField 删除 fields 中的 fd，返回的是一个新的切片
+func removeField(fields []*Field, fd *Field) []*Field {
+	res := make([]*Field, 0, len(fields))
+	for _, f := range fields {
+		if f != fd {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -0,0 +1,287 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/borntodie-new/orm-framework/model"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type TestTagModel struct {
+	Id        int64  `orm:"pk, auto_increment"`
+	Email     string `orm:"column=user_email,size=128,unique,index=idx_email"`
+	Status    int8   `orm:"default=1,type=tinyint"`
+	Nickname  string `orm:"nullable,default="`
+	CreatedAt int64  `orm:"readonly"`
+	Ignored   string `orm:"-"`
+}
+
+type TestUnknownTagModel struct {
+	Name string `orm:"colum=name"`
+}
+
+type TestFlagWithValueModel struct {
+	Id int64 `orm:"pk=true"`
+}
+
+type TestKeyWithoutValueModel struct {
+	Name string `orm:"index"`
+}
+
+type TestInvalidSizeModel struct {
+	Name string `orm:"size=abc"`
+}
+
+func TestManager_Tag(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name      string
+		entity    any
+		wantModel *model.Model
+		wantErr   error
+	}{
+		{
+			name:   "test full tags",
+			entity: &TestTagModel{},
+			wantModel: func() *model.Model {
+				typ := reflect.TypeOf(TestTagModel{})
+				fields := []*model.Field{
+					{FieldName: "Id", ColumnName: "id", Type: typ.Field(0).Type, Offset: typ.Field(0).Offset, FieldIndex: []int{0}, PrimaryKey: true, AutoIncrement: true},
+					{FieldName: "Email", ColumnName: "user_email", Type: typ.Field(1).Type, Offset: typ.Field(1).Offset, FieldIndex: []int{1}, Size: 128, Unique: true, Index: "idx_email"},
+					{FieldName: "Status", ColumnName: "status", Type: typ.Field(2).Type, Offset: typ.Field(2).Offset, FieldIndex: []int{2}, Default: "1", HasDefault: true, SQLType: "tinyint"},
+					{FieldName: "Nickname", ColumnName: "nickname", Type: typ.Field(3).Type, Offset: typ.Field(3).Offset, FieldIndex: []int{3}, Nullable: true, HasDefault: true},
+					{FieldName: "CreatedAt", ColumnName: "created_at", Type: typ.Field(4).Type, Offset: typ.Field(4).Offset, FieldIndex: []int{4}, Readonly: true},
+				}
+				m := &model.Model{
+					TableName:     "test_tag_model",
+					FieldsMap:     make(map[string]*model.Field, len(fields)),
+					ColumnsMap:    make(map[string]*model.Field, len(fields)),
+					Fields:        fields,
+					PrimaryKeys:   fields[:1],
+					AutoIncrement: fields[0],
+				}
+				for _, f := range fields {
+					m.FieldsMap[f.FieldName] = f
+					m.ColumnsMap[f.ColumnName] = f
+				}
+				return m
+			}(),
+		},
+		{
+			name:    "test unknown tag",
+			entity:  &TestUnknownTagModel{},
+			wantErr: errs.NewErrUnknownTag("colum"),
+		},
+		{
+			name:    "test flag with value",
+			entity:  &TestFlagWithValueModel{},
+			wantErr: errs.NewErrInvalidTagContext("pk=true"),
+		},
+		{
+			name:    "test key without value",
+			entity:  &TestKeyWithoutValueModel{},
+			wantErr: errs.NewErrInvalidTagContext("index"),
+		},
+		{
+			name:    "test invalid size",
+			entity:  &TestInvalidSizeModel{},
+			wantErr: errs.NewErrInvalidTagContext("size=abc"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := db.manager.Get(tc.entity)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantModel, m)
+		})
+	}
+}
+
+type TestTagValueModel struct {
+	Price float64 `orm:"type=decimal(10,2),default=0"`
+	Kind  string  `orm:"type=enum('a','b'), default='a,b'"`
+	Expr  string  `orm:"default=a=b,nullable"`
+}
+
+type TestUnclosedTagModel struct {
+	Price float64 `orm:"type=decimal(10,2"`
+}
+
+func TestManager_TagValue(t *testing.T) {
+	db := memoryDB(t)
+	m, err := db.manager.Get(&TestTagValueModel{})
+	assert.NoError(t, err)
+	testCases := []struct {
+		name        string
+		fieldName   string
+		wantType    string
+		wantDefault string
+		nullable    bool
+	}{
+		{
+			name:        "test comma in parentheses",
+			fieldName:   "Price",
+			wantType:    "decimal(10,2)",
+			wantDefault: "0",
+		},
+		{
+			name:        "test comma in quotes",
+			fieldName:   "Kind",
+			wantType:    "enum('a','b')",
+			wantDefault: "'a,b'",
+		},
+		{
+			name:        "test equal sign in value",
+			fieldName:   "Expr",
+			wantDefault: "a=b",
+			nullable:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fd := m.FieldsMap[tc.fieldName]
+			assert.Equal(t, tc.wantType, fd.SQLType)
+			assert.Equal(t, tc.wantDefault, fd.Default)
+			assert.True(t, fd.HasDefault)
+			assert.Equal(t, tc.nullable, fd.Nullable)
+		})
+	}
+
+	_, err = db.manager.Get(&TestUnclosedTagModel{})
+	assert.Equal(t, errs.NewErrInvalidTagContext("type=decimal(10,2"), err)
+}
+
+func TestInsertSQL_TagBuild(t *testing.T) {
+	db := memoryDB(t)
+	res, err := NewInsertSQL[TestTagModel](db).Values(&TestTagModel{Email: "neo@test.com", CreatedAt: 10, Ignored: "ignored"}).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &SQLInfo{
+		SQL:  "INSERT INTO `test_tag_model` (`user_email`, `status`, `nickname`) VALUES (?, ?, ?);",
+		Args: []any{"neo@test.com", int8(0), ""},
+	}, res)
+}
+
+type TestBaseModel struct {
+	Id        int64 `orm:"pk,auto_increment"`
+	CreatedAt int64
+}
+
+type TestAddress struct {
+	City   string
+	Street string `orm:"column=road"`
+}
+
+type TestEmbeddedModel struct {
+	TestBaseModel
+	Name string
+	Addr TestAddress `orm:"embedded,prefix=addr_"`
+}
+
+type TestEmbeddedPointerModel struct {
+	*TestBaseModel
+	Name string
+}
+
+type TestEmbeddedFieldCollisionModel struct {
+	TestBaseModel
+	Id int64
+}
+
+type TestEmbeddedColumnCollisionModel struct {
+	TestBaseModel
+	Home TestAddress `orm:"embedded"`
+	Work TestAddress `orm:"embedded"`
+}
+
+type TestPrefixWithoutEmbeddedModel struct {
+	Name string `orm:"prefix=addr_"`
+}
+
+func TestManager_Embedded(t *testing.T) {
+	db := memoryDB(t)
+	typ := reflect.TypeOf(TestEmbeddedModel{})
+	base := reflect.TypeOf(TestBaseModel{})
+	addr := reflect.TypeOf(TestAddress{})
+	m, err := db.manager.Get(&TestEmbeddedModel{})
+	assert.NoError(t, err)
+	fields := []*model.Field{
+		{FieldName: "Id", ColumnName: "id", Type: base.Field(0).Type, Offset: base.Field(0).Offset, FieldIndex: []int{0, 0}, PrimaryKey: true, AutoIncrement: true},
+		{FieldName: "CreatedAt", ColumnName: "created_at", Type: base.Field(1).Type, Offset: base.Field(1).Offset, FieldIndex: []int{0, 1}},
+		{FieldName: "Name", ColumnName: "name", Type: typ.Field(1).Type, Offset: typ.Field(1).Offset, FieldIndex: []int{1}},
+		{FieldName: "Addr.City", ColumnName: "addr_city", Type: addr.Field(0).Type, Offset: typ.Field(2).Offset + addr.Field(0).Offset, FieldIndex: []int{2, 0}},
+		{FieldName: "Addr.Street", ColumnName: "addr_road", Type: addr.Field(1).Type, Offset: typ.Field(2).Offset + addr.Field(1).Offset, FieldIndex: []int{2, 1}},
+	}
+	assert.Equal(t, fields, m.Fields)
+	assert.Equal(t, fields[:1], m.PrimaryKeys)
+	assert.Equal(t, fields[0], m.AutoIncrement)
+	assert.Equal(t, fields[3], m.FieldsMap["Addr.City"])
+	assert.Equal(t, fields[4], m.ColumnsMap["addr_road"])
+
+	testCases := []struct {
+		name    string
+		entity  any
+		wantErr error
+	}{
+		{
+			name:    "test embedded pointer",
+			entity:  &TestEmbeddedPointerModel{},
+			wantErr: errs.NewErrNotSupportEmbedded("TestBaseModel"),
+		},
+		{
+			name:    "test field collision",
+			entity:  &TestEmbeddedFieldCollisionModel{},
+			wantErr: errs.NewErrDuplicateField("Id"),
+		},
+		{
+			name:    "test column collision",
+			entity:  &TestEmbeddedColumnCollisionModel{},
+			wantErr: errs.NewErrDuplicateColumn("city"),
+		},
+		{
+			name:    "test prefix without embedded",
+			entity:  &TestPrefixWithoutEmbeddedModel{},
+			wantErr: errs.NewErrInvalidTagContext("prefix=addr_"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := db.manager.Get(tc.entity)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestEmbedded_InsertAndSelect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	for _, v := range []valuer.FactoryValuer{valuer.NewUnsafeValuer, valuer.NewReflectValuer} {
+		db, err := Open("sqlite3", "file:embedded.db?cache=shared&mode=memory", DBWithDialect(SQLite3), DBWithValuer(v))
+		assert.NoError(t, err)
+		_, err = db.db.ExecContext(ctx, "DROP TABLE IF EXISTS `test_embedded_model`;")
+		assert.NoError(t, err)
+		_, err = db.db.ExecContext(ctx, "CREATE TABLE `test_embedded_model` (`id` INTEGER PRIMARY KEY AUTOINCREMENT, `created_at` INTEGER, `name` TEXT, `addr_city` TEXT, `addr_road` TEXT);")
+		assert.NoError(t, err)
+
+		val := &TestEmbeddedModel{
+			TestBaseModel: TestBaseModel{CreatedAt: 100},
+			Name:          "Neo",
+			Addr:          TestAddress{City: "Shanghai", Street: "Nanjing Road"},
+		}
+		res, err := NewInsertSQL[TestEmbeddedModel](db).Values(val).ExecuteWithContext(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, res.err)
+		assert.Equal(t, int64(1), val.Id)
+
+		found, err := NewSelectSQL[TestEmbeddedModel](db, nil).Where(F("Addr.City").EQ("Shanghai")).QueryRawWithContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, val, found)
+	}
+}
@@ -0,0 +1,216 @@
+package orm_framework
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNamingStrategy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		naming     model.NamingStrategy
+		input      string
+		wantTable  string
+		wantColumn string
+	}{
+		{
+			name:       "test snake case",
+			naming:     model.SnakeCase,
+			input:      "TestModel",
+			wantTable:  "test_model",
+			wantColumn: "test_model",
+		},
+		{
+			name:       "test snake case acronym at end",
+			naming:     model.SnakeCase,
+			input:      "UserID",
+			wantTable:  "user_id",
+			wantColumn: "user_id",
+		},
+		{
+			name:       "test snake case acronym at start",
+			naming:     model.SnakeCase,
+			input:      "HTTPServer",
+			wantTable:  "http_server",
+			wantColumn: "http_server",
+		},
+		{
+			name:       "test snake case with digits",
+			naming:     model.SnakeCase,
+			input:      "OAuth2Token",
+			wantTable:  "o_auth2_token",
+			wantColumn: "o_auth2_token",
+		},
+		{
+			name:       "test snake case unicode",
+			naming:     model.SnakeCase,
+			input:      "ÜberNamé",
+			wantTable:  "über_namé",
+			wantColumn: "über_namé",
+		},
+		{
+			name:       "test identity",
+			naming:     model.Identity,
+			input:      "UserID",
+			wantTable:  "UserID",
+			wantColumn: "UserID",
+		},
+		{
+			name:       "test table prefix",
+			naming:     model.TablePrefix("tbl_", model.SnakeCase),
+			input:      "UserInfo",
+			wantTable:  "tbl_user_info",
+			wantColumn: "user_info",
+		},
+		{
+			name:       "test plural",
+			naming:     model.PluralTable(model.SnakeCase),
+			input:      "UserCategory",
+			wantTable:  "user_categories",
+			wantColumn: "user_category",
+		},
+		{
+			name:       "test plural es",
+			naming:     model.PluralTable(model.SnakeCase),
+			input:      "UserAddress",
+			wantTable:  "user_addresses",
+			wantColumn: "user_address",
+		},
+		{
+			name:       "test plural vowel y",
+			naming:     model.PluralTable(model.SnakeCase),
+			input:      "Day",
+			wantTable:  "days",
+			wantColumn: "day",
+		},
+		{
+			name:       "test prefix and plural",
+			naming:     model.TablePrefix("tbl_", model.PluralTable(model.SnakeCase)),
+			input:      "User",
+			wantTable:  "tbl_users",
+			wantColumn: "user",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantTable, tc.naming.TableName(tc.input))
+			assert.Equal(t, tc.wantColumn, tc.naming.ColumnName(tc.input))
+		})
+	}
+}
+
+type TestNamingModel struct {
+	UserID    int64
+	FirstName string
+	Nickname  string `orm:"column=nick"`
+}
+
+type TestNamingTableModel struct {
+	Id int64
+}
+
+func (t TestNamingTableModel) TableName() string {
+	return "naming_table"
+}
+
+func TestDBWithNamingStrategy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		naming  model.NamingStrategy
+		builder func(db *DB) Builder
+		wantSQL string
+	}{
+		{
+			name:   "test default",
+			naming: nil,
+			builder: func(db *DB) Builder {
+				return NewSelectSQL[TestNamingModel](db, nil).Where(F("UserID").EQ(1))
+			},
+			wantSQL: "SELECT * FROM `test_naming_model` WHERE (`user_id` = ?);",
+		},
+		{
+			name:   "test legacy schema",
+			naming: model.TablePrefix("tbl_", model.PluralTable(model.SnakeCase)),
+			builder: func(db *DB) Builder {
+				return NewSelectSQL[TestNamingModel](db, nil).Fields(F("FirstName"), F("Nickname")).Where(F("UserID").EQ(1))
+			},
+			wantSQL: "SELECT `first_name`, `nick` FROM `tbl_test_naming_models` WHERE (`user_id` = ?);",
+		},
+		{
+			name:   "test identity",
+			naming: model.Identity,
+			builder: func(db *DB) Builder {
+				return NewSelectSQL[TestNamingModel](db, nil).Fields(F("FirstName")).Where(F("UserID").EQ(1))
+			},
+			wantSQL: "SELECT `FirstName` FROM `TestNamingModel` WHERE (`UserID` = ?);",
+		},
+		{
+			name:   "test table name interface",
+			naming: model.TablePrefix("tbl_", model.SnakeCase),
+			builder: func(db *DB) Builder {
+				return NewSelectSQL[TestNamingTableModel](db, nil)
+			},
+			wantSQL: "SELECT * FROM `naming_table`;",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []DBOption
+			if tc.naming != nil {
+				opts = append(opts, DBWithNamingStrategy(tc.naming))
+			}
+			db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory", opts...)
+			assert.NoError(t, err)
+			res, err := tc.builder(db).Build()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, res.SQL)
+		})
+	}
+}
+
+func TestDBWithNamingStrategyAndManager(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	// 空的管理器，两个配置的顺序不影响结果
+	for _, opts := range [][]func(m *model.Manager) DBOption{
+		{DBWithManager, func(*model.Manager) DBOption { return DBWithNamingStrategy(model.Identity) }},
+		{func(*model.Manager) DBOption { return DBWithNamingStrategy(model.Identity) }, DBWithManager},
+	} {
+		manager := model.NewManager()
+		db, err := OpenDB(mockDB, opts[0](manager), opts[1](manager))
+		assert.NoError(t, err)
+		res, err := NewSelectSQL[TestNamingModel](db, nil).Build()
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM `TestNamingModel`;", res.SQL)
+		// 命名策略设置在传入的管理器上
+		m, err := manager.Get(&TestNamingModel{})
+		assert.NoError(t, err)
+		assert.Equal(t, "TestNamingModel", m.TableName)
+	}
+
+	// 已经有表模型的管理器不能再修改命名策略
+	manager := model.NewManager()
+	_, err = model.Register[TestNamingModel](manager, model.WithTableName("tbl_users"))
+	assert.NoError(t, err)
+	_, err = OpenDB(mockDB, DBWithManager(manager), DBWithNamingStrategy(model.Identity))
+	assert.Equal(t, errs.ErrNamingStrategyWithModels, err)
+	_, err = OpenDB(mockDB, DBWithNamingStrategy(model.Identity), DBWithManager(manager))
+	assert.Equal(t, errs.ErrNamingStrategyWithModels, err)
+
+	// 冻结的管理器也不能修改命名策略
+	manager.Freeze()
+	_, err = OpenDB(mockDB, DBWithManager(manager), DBWithNamingStrategy(model.Identity))
+	assert.Equal(t, errs.ErrManagerFrozen, err)
+	// 注册的表模型和冻结都还在
+	db, err := OpenDB(mockDB, DBWithManager(manager))
+	assert.NoError(t, err)
+	res, err := NewSelectSQL[TestNamingModel](db, nil).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `tbl_users`;", res.SQL)
+	_, err = NewSelectSQL[TestModel](db, nil).Build()
+	assert.Equal(t, errs.NewErrModelNotRegistered("TestModel"), err)
+}
@@ -0,0 +1,108 @@
+package orm_framework
+
+import (
+	"context"
+	"database/sql"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/borntodie-new/orm-framework/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type TestNullableModel struct {
+	Id    int64
+	Name  *string
+	Age   sql.NullInt64
+	Email string `orm:"null_as_zero"`
+	Score int64
+}
+
+func TestNullable_Build(t *testing.T) {
+	db := memoryDB(t)
+	name := "Neo"
+	testCases := []struct {
+		name    string
+		b       Builder
+		wantRes *SQLInfo
+	}{
+		{
+			name: "test insert nil pointer",
+			b:    NewInsertSQL[TestNullableModel](db).Values(&TestNullableModel{Id: 1}, &TestNullableModel{Id: 2, Name: &name}),
+			wantRes: &SQLInfo{
+				SQL: "INSERT INTO `test_nullable_model` (`id`, `name`, `age`, `email`, `score`) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?);",
+				Args: []any{
+					int64(1), nil, sql.NullInt64{}, "", int64(0),
+					int64(2), &name, sql.NullInt64{}, "", int64(0),
+				},
+			},
+		},
+		{
+			name: "test update nil pointer",
+			b:    NewUpdateSQL[TestNullableModel](db).Values("Name", (*string)(nil)).Where(F("Id").EQ(1)),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_nullable_model` SET `name` = ? WHERE (`id` = ?);",
+				Args: []any{nil, 1},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.b.Build()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestNullable_Query(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	name := "Neo"
+	for _, v := range []valuer.FactoryValuer{valuer.NewUnsafeValuer, valuer.NewReflectValuer} {
+		db, err := Open("sqlite3", "file:nullable.db?cache=shared&mode=memory", DBWithDialect(SQLite3), DBWithValuer(v))
+		assert.NoError(t, err)
+		_, err = db.db.ExecContext(ctx, "DROP TABLE IF EXISTS `test_nullable_model`;")
+		assert.NoError(t, err)
+		_, err = db.db.ExecContext(ctx, "CREATE TABLE `test_nullable_model` (`id` INTEGER PRIMARY KEY, `name` TEXT, `age` INTEGER, `email` TEXT, `score` INTEGER);")
+		assert.NoError(t, err)
+		_, err = NewInsertSQL[TestNullableModel](db).
+			Values(&TestNullableModel{Id: 1, Name: &name, Age: sql.NullInt64{Int64: 18, Valid: true}, Email: "neo@test.com", Score: 90}).
+			ExecuteWithContext(ctx)
+		assert.NoError(t, err)
+		_, err = db.db.ExecContext(ctx, "INSERT INTO `test_nullable_model` (`id`, `score`) VALUES (2, 60), (3, NULL);")
+		assert.NoError(t, err)
+
+		// 有值的时候正常映射
+		found, err := NewSelectSQL[TestNullableModel](db, nil).Where(F("Id").EQ(1)).QueryRawWithContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &TestNullableModel{Id: 1, Name: &name, Age: sql.NullInt64{Int64: 18, Valid: true}, Email: "neo@test.com", Score: 90}, found)
+
+		// 指针字段和 sql.Null* 字段本身就能处理 NULL，开启了 null_as_zero 的字段是零值
+		found, err = NewSelectSQL[TestNullableModel](db, nil).Where(F("Id").EQ(2)).QueryRawWithContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &TestNullableModel{Id: 2, Score: 60}, found)
+
+		// 没有开启 null_as_zero 的普通字段是 NULL 的时候返回错误
+		_, err = NewSelectSQL[TestNullableModel](db, nil).Where(F("Id").EQ(3)).QueryRawWithContext(ctx)
+		assert.Error(t, err)
+
+		// 在表模型上开启 NullAsZero
+		manager := model.NewManager()
+		_, err = model.Register[TestNullableModel](manager, model.WithNullAsZero())
+		assert.NoError(t, err)
+		zeroDB, err := Open("sqlite3", "file:nullable.db?cache=shared&mode=memory", DBWithDialect(SQLite3), DBWithValuer(v), DBWithManager(manager))
+		assert.NoError(t, err)
+		found, err = NewSelectSQL[TestNullableModel](zeroDB, nil).Where(F("Id").EQ(3)).QueryRawWithContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &TestNullableModel{Id: 3}, found)
+
+		// 更新成 NULL
+		_, err = NewUpdateSQL[TestNullableModel](db).Values("Name", (*string)(nil)).Where(F("Id").EQ(1)).ExecuteWithContext(ctx)
+		assert.NoError(t, err)
+		found, err = NewSelectSQL[TestNullableModel](db, nil).Where(F("Id").EQ(1)).QueryRawWithContext(ctx)
+		assert.NoError(t, err)
+		assert.Nil(t, found.Name)
+	}
+}
@@ -0,0 +1,41 @@
+package orm_framework
+
+// Order 排序条件，用于构建 ORDER BY 子句
+// Go中的使用：OrderBy(Asc("Age"), Desc("Id"))
+// SQL中的使用：ORDER BY `age` ASC, `id` DESC
+type Order struct {
+	// field 排序的列，可以带上表的别名
+	field Field
+	// order 排序方式，ASC 或 DESC
+	order string
+}
+
+// Asc 升序
+func Asc(fieldName string) Order {
+	return F(fieldName).Asc()
+}
+
+// Desc 降序
+func Desc(fieldName string) Order {
+	return F(fieldName).Desc()
+}
+
+// Asc 按照这一列升序，JOIN 查询的时候可以指定是哪张表的列
+// Go中的使用：OrderBy(u.F("Name").Asc())
+// SQL中的使用：ORDER BY `u`.`name` ASC
+func (f Field) Asc() Order {
+	return Order{
+		field: f,
+		order: "ASC",
+	}
+}
+
+// Desc 按照这一列降序
+// Go中的使用：OrderBy(u.F("Name").Desc())
+// SQL中的使用：ORDER BY `u`.`name` DESC
+func (f Field) Desc() Order {
+	return Order{
+		field: f,
+		order: "DESC",
+	}
+}
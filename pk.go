@@ -0,0 +1,107 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/borntodie-new/orm-framework/model"
+)
+
+// pkChunkSize FindByPKs 每次查询的主键个数
+// 主键太多的时候 IN 的参数会超过数据库的限制，比如 SQLite 默认最多只能有 999 个参数
+const pkChunkSize = 500
+
+// FindByPK 根据主键查询一条数据，联合主键的时候按照字段的顺序传入多个值
+// Go中的使用：FindByPK[User](ctx, db, valuer.NewUnsafeValuer, 12)
+// SQL中的使用：SELECT * FROM `user` WHERE (`id` = ?) LIMIT ?;
+func FindByPK[T any](ctx context.Context, sess Session, valuer valuer.FactoryValuer, id ...any) (*T, error) {
+	where, err := pkPredicate[T](sess, id)
+	if err != nil {
+		return nil, err
+	}
+	return NewSelectSQL[T](sess, valuer).Where(where).QueryRawWithContext(ctx)
+}
+
+// FindByPKs 根据多个主键查询数据，主键很多的时候会分批查询
+// 单个主键的时候 ids 中每个元素就是主键的值，使用 IN 查询
+// 联合主键的时候 ids 中每个元素都是一个 []any，按照字段的顺序保存主键的值，使用 OR 连接
+// Go中的使用：FindByPKs[User](ctx, db, valuer.NewUnsafeValuer, 1, 2, 3)
+// SQL中的使用：SELECT * FROM `user` WHERE (`id` IN (?, ?, ?));
+func FindByPKs[T any](ctx context.Context, sess Session, valuer valuer.FactoryValuer, ids ...any) ([]*T, error) {
+	pks, err := primaryKeys[T](sess)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*T, 0, len(ids))
+	for start := 0; start < len(ids); start += pkChunkSize {
+		end := start + pkChunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var where Predicate
+		if len(pks) == 1 {
+			where = F(pks[0].FieldName).In(ids[start:end]...)
+		} else {
+			for idx, id := range ids[start:end] {
+				vals, ok := id.([]any)
+				if !ok {
+					return nil, errs.NewErrPrimaryKeyValues(len(pks), 1)
+				}
+				p, err := pkPredicate[T](sess, vals)
+				if err != nil {
+					return nil, err
+				}
+				if idx == 0 {
+					where = p
+				} else {
+					where = where.OR(p)
+				}
+			}
+		}
+		chunk, err := NewSelectSQL[T](sess, valuer).Where(where).QueryWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, chunk...)
+	}
+	return res, nil
+}
+
+// DeleteByPK 根据主键删除一条数据，联合主键的时候按照字段的顺序传入多个值
+// Go中的使用：DeleteByPK[User](ctx, db, 12)
+// SQL中的使用：DELETE FROM `user` WHERE (`id` = ?);
+func DeleteByPK[T any](ctx context.Context, sess Session, id ...any) (*Result, error) {
+	where, err := pkPredicate[T](sess, id)
+	if err != nil {
+		return nil, err
+	}
+	return NewDeleteSQL[T](sess).Where(where).ExecuteWithContext(ctx)
+}
+
+// primaryKeys 获取 T 的主键字段
+func primaryKeys[T any](sess Session) ([]*model.Field, error) {
+	m, err := sess.getCore().manager.Get(new(T))
+	if err != nil {
+		return nil, err
+	}
+	if len(m.PrimaryKeys) <= 0 {
+		return nil, errs.ErrNoPrimaryKey
+	}
+	return m.PrimaryKeys, nil
+}
+
+// pkPredicate 构建一条数据的主键条件，联合主键的多个字段之间用 AND 连接
+func pkPredicate[T any](sess Session, id []any) (Predicate, error) {
+	pks, err := primaryKeys[T](sess)
+	if err != nil {
+		return Predicate{}, err
+	}
+	if len(pks) != len(id) {
+		return Predicate{}, errs.NewErrPrimaryKeyValues(len(pks), len(id))
+	}
+	p := F(pks[0].FieldName).EQ(id[0])
+	for i := 1; i < len(pks); i++ {
+		p = p.AND(F(pks[i].FieldName).EQ(id[i]))
+	}
+	return p, nil
+}
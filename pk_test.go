@@ -0,0 +1,129 @@
+package orm_framework
+
+import (
+	"context"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type TestUserRole struct {
+	UserId int64 `orm:"pk"`
+	RoleId int64 `orm:"column=role,pk"`
+	Name   string
+}
+
+type TestNoPK struct {
+	Name string
+}
+
+func TestPrimaryKeys(t *testing.T) {
+	db := memoryDB(t)
+	m, err := db.manager.Get(&TestModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(m.PrimaryKeys))
+	assert.Equal(t, "Id", m.PrimaryKeys[0].FieldName)
+	assert.True(t, m.FieldsMap["Id"].PrimaryKey)
+
+	m, err = db.manager.Get(&TestUserRole{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(m.PrimaryKeys))
+	assert.Equal(t, "UserId", m.PrimaryKeys[0].FieldName)
+	assert.Equal(t, "RoleId", m.PrimaryKeys[1].FieldName)
+	assert.Equal(t, "role", m.PrimaryKeys[1].ColumnName)
+	assert.False(t, m.FieldsMap["Name"].PrimaryKey)
+
+	m, err = db.manager.Get(&TestNoPK{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(m.PrimaryKeys))
+}
+
+func TestFindByPK(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("find_by_pk", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_user_role` (`user_id` INTEGER, `role` INTEGER, `name` TEXT, PRIMARY KEY (`user_id`, `role`));")
+	assert.NoError(t, err)
+	vals := make([]string, 0, 120)
+	for i := 1; i <= 120; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, 'user%d', %d)", i, i, i%100))
+	}
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_model` (`id`, `first_name`, `age`) VALUES "+strings.Join(vals, ", ")+";")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_user_role` VALUES (1, 1, 'admin'), (1, 2, 'editor'), (2, 1, 'admin');")
+	assert.NoError(t, err)
+
+	res, err := FindByPK[TestModel](ctx, db, valuer.NewUnsafeValuer, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 12, FirstName: "user12", Age: 12}, res)
+	_, err = FindByPK[TestModel](ctx, db, valuer.NewUnsafeValuer, 1000)
+	assert.Equal(t, errs.ErrNoRows, err)
+	_, err = FindByPK[TestModel](ctx, db, valuer.NewUnsafeValuer, 1, 2)
+	assert.Equal(t, errs.NewErrPrimaryKeyValues(1, 2), err)
+	_, err = FindByPK[TestNoPK](ctx, db, valuer.NewUnsafeValuer, 1)
+	assert.Equal(t, errs.ErrNoPrimaryKey, err)
+
+	role, err := FindByPK[TestUserRole](ctx, db, valuer.NewUnsafeValuer, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestUserRole{UserId: 1, RoleId: 2, Name: "editor"}, role)
+	_, err = FindByPK[TestUserRole](ctx, db, valuer.NewUnsafeValuer, 1)
+	assert.Equal(t, errs.NewErrPrimaryKeyValues(2, 1), err)
+
+	roles, err := FindByPKs[TestUserRole](ctx, db, valuer.NewReflectValuer, []any{1, 1}, []any{2, 1}, []any{3, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestUserRole{{UserId: 1, RoleId: 1, Name: "admin"}, {UserId: 2, RoleId: 1, Name: "admin"}}, roles)
+	_, err = FindByPKs[TestUserRole](ctx, db, valuer.NewReflectValuer, 1, 2)
+	assert.Equal(t, errs.NewErrPrimaryKeyValues(2, 1), err)
+
+	ids := make([]any, 0, 1200)
+	for i := 1; i <= 1200; i++ {
+		ids = append(ids, i)
+	}
+	list, err := FindByPKs[TestModel](ctx, db, valuer.NewUnsafeValuer, ids...)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, len(list))
+
+	// 删除之后就查不到了
+	_, err = DeleteByPK[TestUserRole](ctx, db, 1, 1)
+	assert.NoError(t, err)
+	_, err = FindByPK[TestUserRole](ctx, db, valuer.NewUnsafeValuer, 1, 1)
+	assert.Equal(t, errs.ErrNoRows, err)
+	_, err = DeleteByPK[TestModel](ctx, db)
+	assert.Equal(t, errs.NewErrPrimaryKeyValues(1, 0), err)
+}
+
+func TestFindByPKs_Chunk(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+
+	ids := make([]any, 0, pkChunkSize+2)
+	for i := 1; i <= pkChunkSize+2; i++ {
+		ids = append(ids, i)
+	}
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE \\(`id` IN \\(\\?(, \\?){499}\\)\\);").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE \\(`id` IN \\(\\?, \\?\\)\\);").
+		WithArgs(pkChunkSize+1, pkChunkSize+2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	res, err := FindByPKs[TestModel](ctx, db, valuer.NewUnsafeValuer, ids...)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(res))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	res, err = FindByPKs[TestModel](ctx, db, valuer.NewUnsafeValuer)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestModel{}, res)
+}
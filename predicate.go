@@ -7,14 +7,24 @@ func (o opType) String() string {
 }
 
 const (
-	EQType  = " = "
-	GTType  = " > "
-	GTEType = " >= "
-	LTType  = " < "
-	LTEType = " <= "
-	ANDType = " AND "
-	ORType  = " OR "
-	NOTType = "NOT "
+	EQType        = " = "
+	NEQType       = " != "
+	GTType        = " > "
+	GTEType       = " >= "
+	LTType        = " < "
+	LTEType       = " <= "
+	INType        = " IN "
+	NOTINType     = " NOT IN "
+	LIKEType      = " LIKE "
+	NOTLIKEType   = " NOT LIKE "
+	BETWEENType   = " BETWEEN "
+	ISNULLType    = " IS NULL"
+	ISNOTNULLType = " IS NOT NULL"
+	ANDType       = " AND "
+	ORType        = " OR "
+	NOTType       = "NOT "
+	EXISTSType    = "EXISTS "
+	NOTEXISTSType = "NOT EXISTS "
 )
 
 // Predicate 谓词，用于拼接WHERE条件的
@@ -34,6 +44,21 @@ func (p Predicate) expr() {
 	panic("implement me")
 }
 
+// precedence 运算符的优先级，数字越大优先级越高
+// 比较运算符构建的时候总是会加上括号，所以优先级是最高的
+func (p Predicate) precedence() int {
+	switch p.op {
+	case ORType:
+		return 1
+	case ANDType:
+		return 2
+	case NOTType:
+		return 3
+	default:
+		return 4
+	}
+}
+
 // AND 实现SQL中的 AND 语句
 // Go中的使用：F("Id").EQ(12).AND(F("FirstName").EQ("Neo"))
 // SQL中的使用：WHERE Id = 12 AND FirstName = "Neo"
@@ -17,8 +17,8 @@ type RawSQL[T any] struct {
 	sql string
 	// args SQL 所需的参数
 	args []any
-	// db 全局的、自定义的数据库连接对象
-	db *DB
+	// sess 执行语句的会话，可以是 DB 也可以是 Tx
+	sess Session
 	// models 维护一个表模型
 	model *model.Model
 	// valuer 公共映射值方法
@@ -29,7 +29,7 @@ type RawSQL[T any] struct {
 //	// 最终的结果
 //	tp := new(T)
 //	//var t T
-//	//m, err := s.db.manager.Get(t)
+//	//m, err := s.sess.getCore().manager.Get(t)
 //	//if err != nil {
 //	//	return nil, err
 //	//}
@@ -74,10 +74,12 @@ func (r *RawSQL[T]) QueryWithContext(ctx context.Context) ([]*T, error) {
 		return nil, err
 	}
 	// 执行 SQL 语句
-	res, err := r.db.db.QueryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := r.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return nil, err
 	}
+	// 一定要关闭结果集，否则连接不会被释放，在事务中还会导致后续的语句无法执行
+	defer res.Close()
 	tps := make([]*T, 0)
 	for res.Next() {
 		tp := new(T)
@@ -98,10 +100,11 @@ func (r *RawSQL[T]) QueryRawWithContext(ctx context.Context) (*T, error) {
 		return nil, err
 	}
 	// 执行 SQL 语句
-	res, err := r.db.db.QueryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := r.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Close()
 	if !res.Next() {
 		return nil, errs.ErrNoRows
 	}
@@ -113,7 +116,7 @@ func (r *RawSQL[T]) QueryRawWithContext(ctx context.Context) (*T, error) {
 
 func (r *RawSQL[T]) Build() (*SQLInfo, error) {
 	var err error
-	r.model, err = r.db.manager.Get(new(T))
+	r.model, err = r.sess.getCore().manager.Get(new(T))
 	if r.sql == "" {
 		return nil, errs.ErrNoSQL
 	}
@@ -123,13 +126,17 @@ func (r *RawSQL[T]) Build() (*SQLInfo, error) {
 	}, err
 }
 
-func NewRawSQL[T any](db *DB, valuer valuer.FactoryValuer, sql string, args ...any) *RawSQL[T] {
+func NewRawSQL[T any](sess Session, valuer valuer.FactoryValuer, sql string, args ...any) *RawSQL[T] {
 	// 为什么不在这里将 model 初始化好？
 	// 为了不打断我们链式调用，因为获取 model 可能会出现错误，如果将 error 返回，就会打断链式调用
+	// 没有指定的时候用 DB 上默认的映射字段接口
+	if valuer == nil {
+		valuer = sess.getCore().valuer
+	}
 	return &RawSQL[T]{
 		sql:    sql,
 		args:   args,
-		db:     db,
+		sess:   sess,
 		valuer: valuer,
 	}
 }
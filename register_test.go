@@ -0,0 +1,120 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// TestThirdPartyUser 模拟没办法加标签的第三方结构体
+type TestThirdPartyUser struct {
+	UserNo  int64
+	Name    string
+	Session string
+}
+
+func TestRegister(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []model.ModelOption
+		wantSQL string
+		wantErr error
+	}{
+		{
+			name:    "test no options",
+			wantSQL: "SELECT * FROM `test_third_party_user` WHERE (`user_no` = ?);",
+		},
+		{
+			name: "test all options",
+			opts: []model.ModelOption{
+				model.WithTableName("tbl_users"),
+				model.WithColumnName("UserNo", "id"),
+				model.WithColumnName("Name", "user_name"),
+				model.WithPrimaryKey("UserNo"),
+				model.IgnoreField("Session"),
+			},
+			wantSQL: "SELECT * FROM `tbl_users` WHERE (`id` = ?);",
+		},
+		{
+			name:    "test unknown column field",
+			opts:    []model.ModelOption{model.WithColumnName("Invalid", "id")},
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name:    "test unknown primary key field",
+			opts:    []model.ModelOption{model.WithPrimaryKey("Invalid")},
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name:    "test unknown ignore field",
+			opts:    []model.ModelOption{model.IgnoreField("Invalid")},
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name:    "test duplicate column",
+			opts:    []model.ModelOption{model.WithColumnName("Name", "session")},
+			wantErr: errs.NewErrDuplicateColumn("session"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := &model.Manager{}
+			_, err := model.Register[TestThirdPartyUser](manager, tc.opts...)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			db, err := Open("sqlite3", "file:test.db?cache=shared&mode=memory", DBWithManager(manager))
+			assert.NoError(t, err)
+			res, err := NewSelectSQL[TestThirdPartyUser](db, nil).Where(F("UserNo").EQ(1)).Build()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, res.SQL)
+		})
+	}
+}
+
+func TestRegister_Freeze(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	manager := &model.Manager{}
+	_, err := model.Register[TestThirdPartyUser](manager,
+		model.WithTableName("tbl_users"),
+		model.WithPrimaryKey("UserNo"),
+		model.IgnoreField("Session"))
+	assert.NoError(t, err)
+	manager.Freeze()
+
+	// 冻结之后不能再注册，也不会再自动解析
+	_, err = model.Register[TestModel](manager)
+	assert.Equal(t, errs.ErrManagerFrozen, err)
+	_, err = manager.Get(&TestModel{})
+	assert.Equal(t, errs.NewErrModelNotRegistered("TestModel"), err)
+
+	db, err := Open("sqlite3", "file:register.db?cache=shared&mode=memory", DBWithDialect(SQLite3), DBWithManager(manager))
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `tbl_users` (`user_no` INTEGER PRIMARY KEY, `name` TEXT);")
+	assert.NoError(t, err)
+	_, err = NewInsertSQL[TestThirdPartyUser](db).Values(&TestThirdPartyUser{UserNo: 7, Name: "Neo", Session: "ignored"}).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	found, err := FindByPK[TestThirdPartyUser](ctx, db, nil, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestThirdPartyUser{UserNo: 7, Name: "Neo"}, found)
+	_, err = NewSelectSQL[TestModel](db, nil).Build()
+	assert.Equal(t, errs.NewErrModelNotRegistered("TestModel"), err)
+
+	// 投影查询的 DTO 不需要注册，冻结之后也能使用，但是不会被当成表模型
+	res, err := NewSelectInto[TestThirdPartyUser, TestThirdPartyUserDTO](NewSelectSQL[TestThirdPartyUser](db, nil).
+		Fields(F("Name").As("user_name")).Where(F("UserNo").EQ(7))).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestThirdPartyUserDTO{UserName: "Neo"}, res)
+	_, err = manager.Get(&TestThirdPartyUserDTO{})
+	assert.Equal(t, errs.NewErrModelNotRegistered("TestThirdPartyUserDTO"), err)
+}
+
+type TestThirdPartyUserDTO struct {
+	UserName string
+}
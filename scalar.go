@@ -0,0 +1,62 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+)
+
+// SelectScalar 查询单列的结果，直接扫描到 V 中，不需要经过表模型
+// 主要用于聚合函数，因为聚合函数的别名一般不在 T 的表模型中
+// Go中的使用：NewSelectScalar[float64](NewSelectSQL[User](db, nil).Fields(Avg("Age"))).QueryRawWithContext(ctx)
+// SQL中的使用：SELECT AVG(`age`) FROM `user`;
+// ⚠️：结果可能是 NULL 的时候，V 需要使用 sql.NullFloat64 这种类型
+type SelectScalar[V any] struct {
+	// q 具体的查询语句，只能有一列
+	q QueryBuilder
+}
+
+// NewSelectScalar 初始化单列查询，执行的会话用 q 的
+func NewSelectScalar[V any](q QueryBuilder) *SelectScalar[V] {
+	return &SelectScalar[V]{q: q}
+}
+
+// QueryWithContext 查询多行数据，每行一个值
+func (s *SelectScalar[V]) QueryWithContext(ctx context.Context) ([]V, error) {
+	sqlInfo, err := s.q.Build()
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.q.session().queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	vals := make([]V, 0)
+	for res.Next() {
+		var val V
+		if err = res.Scan(&val); err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, res.Err()
+}
+
+// QueryRawWithContext 查询单个值，没有数据的时候返回 errs.ErrNoRows
+func (s *SelectScalar[V]) QueryRawWithContext(ctx context.Context) (V, error) {
+	var val V
+	sqlInfo, err := s.q.Build()
+	if err != nil {
+		return val, err
+	}
+	res, err := s.q.session().queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return val, err
+	}
+	defer res.Close()
+	if !res.Next() {
+		return val, errs.ErrNoRows
+	}
+	err = res.Scan(&val)
+	return val, err
+}
@@ -16,10 +16,26 @@ type SelectSQL[T any] struct {
 	where []Predicate
 	// args SQL语句中的参数
 	// args []any
-	// db 全局的、自定义的数据库连接对象
-	db *DB
+	// sess 执行语句的会话，可以是 DB 也可以是 Tx
+	sess Session
 	// fields 查询字段
-	fields []Aggregate
+	fields []Selectable
+	// table FROM 后面的表，为空的时候就是 T 对应的表
+	table TableReference
+	// groupBy 分组字段
+	groupBy []Field
+	// having SQL 中的 HAVING 语句
+	having []Predicate
+	// orderBy 排序条件
+	orderBy []Order
+	// limit 最多返回多少条数据，0 表示不限制
+	limit int
+	// offset 跳过多少条数据，0 表示不跳过
+	offset int
+	// ctes WITH 子句中的公用表表达式
+	ctes []cte
+	// distinct 为 true 的时候去掉重复的行
+	distinct bool
 
 	// model 在语句层面维护表模型
 	// model *model.Model
@@ -35,76 +51,112 @@ func (s *SelectSQL[T]) Where(condition ...Predicate) *SelectSQL[T] {
 	return s
 }
 
-func (s *SelectSQL[T]) Fields(fields ...Aggregate) *SelectSQL[T] {
+// Fields 指定查询的列，可以是普通的列，也可以是聚合函数
+func (s *SelectSQL[T]) Fields(fields ...Selectable) *SelectSQL[T] {
 	s.fields = append(s.fields, fields...)
 	return s
 }
 
-func (s *SelectSQL[T]) addArgs(val any) {
-	if val == nil {
-		return
-	}
-	s.args = append(s.args, val)
+// Distinct 去掉重复的行
+// SQL中的使用：SELECT DISTINCT `age` FROM `test_model`
+func (s *SelectSQL[T]) Distinct() *SelectSQL[T] {
+	s.distinct = true
+	return s
 }
 
-// buildWhere 构建 WHERE 语句
-func (s *SelectSQL[T]) buildWhere() error {
-	if len(s.where) <= 0 {
-		return nil
-	}
-	s.sb.WriteString(" WHERE ")
-	p := s.where[0]
-	for i := 1; i <= len(s.where)-1; i++ {
-		p = p.AND(s.where[i])
+// With 定义一个公用表表达式，外层查询通过 CTE[T](name) 引用
+// Go中的使用：With("big_order", NewSelectSQL[Order](db, nil).Where(F("Amount").GT(100))).From(CTE[Order]("big_order"))
+// SQL中的使用：WITH `big_order` AS (SELECT * FROM `order` WHERE (`amount` > ?)) SELECT * FROM `big_order`
+func (s *SelectSQL[T]) With(name string, q QueryBuilder) *SelectSQL[T] {
+	s.ctes = append(s.ctes, cte{name: name, q: q})
+	return s
+}
+
+// WithRecursive 定义一个递归的公用表表达式，anchor 和 recursive 之间用 UNION ALL 连接
+// recursive 中通过 CTE[T](name) 引用自己
+// SQL中的使用：WITH RECURSIVE `tree` AS (SELECT ... UNION ALL SELECT ... JOIN `tree` ...) SELECT * FROM `tree`
+func (s *SelectSQL[T]) WithRecursive(name string, anchor, recursive QueryBuilder) *SelectSQL[T] {
+	s.ctes = append(s.ctes, cte{name: name, q: anchor, recursive: recursive})
+	return s
+}
+
+// GroupBy 设置分组字段
+func (s *SelectSQL[T]) GroupBy(fieldNames ...string) *SelectSQL[T] {
+	for _, fieldName := range fieldNames {
+		s.groupBy = append(s.groupBy, F(fieldName))
 	}
-	return s.buildFields(p)
+	return s
 }
 
-// buildFields 构建WHERE语句
-func (s *SelectSQL[T]) buildFields(exp Expression) error {
-	switch typ := exp.(type) {
-	case nil:
-		return nil
-	case Field:
-		//var t T
-		//m, err := s.db.manager.Get(t)
-		//if err != nil {
-		//	return err
-		//}
-		// 这是纯字段
-		// 注意 Field传入的是Go中的字段名，设置到SQL上的是SQL中的列名
-		s.sb.WriteByte('(')
-		s.sb.WriteByte('`')
-		fd, ok := s.model.FieldsMap[typ.fieldName]
-		if !ok {
-			return errs.NewErrNotSupportUnknownField(typ.fieldName)
-		}
-		s.sb.WriteString(fd.ColumnName)
-		s.sb.WriteByte('`')
-	case Predicate:
-		// 这里需要递归实现，因为是 Predicate 类型，可能是 Field 也可能是 Value
+// GroupByFields 设置分组字段，JOIN 查询的时候可以指定是哪张表的列
+// Go中的使用：GroupByFields(u.F("Name"))
+// SQL中的使用：GROUP BY `u`.`name`
+func (s *SelectSQL[T]) GroupByFields(fields ...Field) *SelectSQL[T] {
+	s.groupBy = append(s.groupBy, fields...)
+	return s
+}
 
-		// 构建左边
-		if err := s.buildFields(typ.left); err != nil {
-			return err
+// Having 设置分组之后的过滤条件，一般是和聚合函数一起用的
+// Go中的使用：GroupBy("Age").Having(Count("Id").GT(2))
+// SQL中的使用：GROUP BY `age` HAVING COUNT(`id`) > 2
+func (s *SelectSQL[T]) Having(condition ...Predicate) *SelectSQL[T] {
+	s.having = append(s.having, condition...)
+	return s
+}
+
+// From 指定 FROM 后面的表，可以是普通的表，也可以是 JOIN 查询
+// Go中的使用：From(TableOf[Order]().As("o").Join(TableOf[User]().As("u")).On(...))
+// 不调用的话默认就是 T 对应的表
+func (s *SelectSQL[T]) From(table TableReference) *SelectSQL[T] {
+	s.table = table
+	return s
+}
+
+// OrderBy 设置排序条件
+func (s *SelectSQL[T]) OrderBy(orders ...Order) *SelectSQL[T] {
+	s.orderBy = append(s.orderBy, orders...)
+	return s
+}
+
+// Limit 设置最多返回多少条数据
+func (s *SelectSQL[T]) Limit(limit int) *SelectSQL[T] {
+	s.limit = limit
+	return s
+}
+
+// Offset 设置跳过多少条数据
+func (s *SelectSQL[T]) Offset(offset int) *SelectSQL[T] {
+	s.offset = offset
+	return s
+}
+
+// buildGroupBy 构建 GROUP BY 子句
+func (s *SelectSQL[T]) buildGroupBy() error {
+	if len(s.groupBy) <= 0 {
+		return nil
+	}
+	s.sb.WriteString(" GROUP BY ")
+	for idx, field := range s.groupBy {
+		if idx > 0 {
+			s.sb.WriteString(", ")
 		}
-		// 构建操作类型
-		s.sb.WriteString(typ.op.String())
-		// 构建右边
-		if err := s.buildFields(typ.right); err != nil {
+		if err := s.buildColumn(field); err != nil {
 			return err
 		}
-	case Value:
-		// 这里是字段值
-		s.sb.WriteString("?")
-		s.addArgs(typ.val)
-		s.sb.WriteByte(')')
-	default:
-		return errs.ErrNotSupportPredicate
 	}
 	return nil
 }
 
+// buildHaving 构建 HAVING 子句
+// 和 WHERE 子句一样，多个条件之间用 AND 连接
+func (s *SelectSQL[T]) buildHaving() error {
+	if len(s.having) <= 0 {
+		return nil
+	}
+	s.sb.WriteString(" HAVING ")
+	return s.buildPredicates(s.having)
+}
+
 //func (s *SelectSQL[T]) setFields(res *sql.Rows) (*T, error) {
 //	// 最终的结果
 //	tp := new(T)
@@ -155,10 +207,12 @@ func (s *SelectSQL[T]) QueryWithContext(ctx context.Context) ([]*T, error) {
 		return nil, err
 	}
 	// 执行 SQL 语句
-	res, err := s.db.db.QueryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := s.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return nil, err
 	}
+	// 一定要关闭结果集，否则连接不会被释放，在事务中还会导致后续的语句无法执行
+	defer res.Close()
 	tps := make([]*T, 0)
 	for res.Next() {
 		// tp, err := s.setFields(res)
@@ -177,16 +231,21 @@ func (s *SelectSQL[T]) QueryWithContext(ctx context.Context) ([]*T, error) {
 // 这里注意一下哈：这是查询单条记录的，但我们内部使用的是查询多条的API
 // 但是但是，我们如果只Scan一次，就表示我们只获取第一条数据
 func (s *SelectSQL[T]) QueryRawWithContext(ctx context.Context) (*T, error) {
+	// 只需要一条数据，所以构建的时候加上 LIMIT 1，避免把整个结果集都查出来
+	limit := s.limit
+	s.limit = 1
 	// 获取 SQL 语句 和 SQL 参数
 	sqlInfo, err := s.Build()
+	s.limit = limit
 	if err != nil {
 		return nil, err
 	}
 	// 执行 SQL 语句
-	res, err := s.db.db.QueryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := s.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Close()
 	if !res.Next() {
 		return nil, errs.ErrNoRows
 	}
@@ -240,38 +299,15 @@ func (s *SelectSQL[T]) QueryRawWithContext(ctx context.Context) (*T, error) {
 }
 
 // buildColumns 构建字段
-// 功能作用和 InsertSQL 中的 buildFields 功能一样，只不过在 SelectSQL 中已经有一个 buildFields 方法了
+// 功能作用和 InsertSQL 中的 buildColumnsAndValues 构建列名的部分一样
 func (s *SelectSQL[T]) buildColumns() error {
 	if len(s.fields) != 0 {
-		for idx, ag := range s.fields {
-			if ag.fieldName == "" {
-				return errs.ErrNoFieldName
-			}
+		for idx, field := range s.fields {
 			if idx > 0 {
 				s.sb.WriteString(", ")
 			}
-			fd, ok := s.model.FieldsMap[ag.fieldName]
-			if !ok {
-				return errs.NewErrNotSupportUnknownField(ag.fieldName)
-			}
-			// 是否是聚合函数操作
-			if ag.fn != "" {
-				s.sb.WriteString(ag.fn.String())
-				s.sb.WriteByte('(')
-			}
-			// 构建普通的列名
-			s.sb.WriteByte('`')
-			s.sb.WriteString(fd.ColumnName)
-			s.sb.WriteByte('`')
-			if ag.fn != "" {
-				s.sb.WriteByte(')')
-			}
-			// 构建列的别名
-			if ag.alias != "" {
-				s.sb.WriteString(" AS ")
-				s.sb.WriteByte('`')
-				s.sb.WriteString(ag.alias)
-				s.sb.WriteByte('`')
+			if err := s.buildSelectable(field); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -308,40 +344,121 @@ func (s *SelectSQL[T]) buildColumns() error {
 	return nil
 }
 
+// buildOrderBy 构建 ORDER BY 子句
+// 和 buildColumns 一样，用户传入的是 Go 中的字段名，需要转换成 SQL 中的列名
+func (s *SelectSQL[T]) buildOrderBy() error {
+	if len(s.orderBy) <= 0 {
+		return nil
+	}
+	s.sb.WriteString(" ORDER BY ")
+	return s.buildOrders(s.orderBy)
+}
+
 func (s *SelectSQL[T]) Build() (*SQLInfo, error) {
-	s.sb.WriteString("SELECT ")
+	s.builder = newBuilder(s.sess)
+	if err := s.build(); err != nil {
+		return nil, err
+	}
+	s.sb.WriteByte(';')
+	res := &SQLInfo{SQL: s.sb.String(), Args: s.args}
+	return res, nil
+}
+
+// buildSubquery 把当前语句作为子查询构建到 parent 中
+// 子查询和 parent 共用同一个 sb 和 args，这样参数的顺序和占位符的序号才是对的
+func (s *SelectSQL[T]) buildSubquery(parent *builder) error {
+	origin := s.builder
+	defer func() {
+		s.builder = origin
+	}()
+	s.builder = &builder{
+		sb:      parent.sb,
+		args:    parent.args,
+		dialect: parent.dialect,
+		manager: parent.manager,
+	}
+	err := s.build()
+	parent.args = s.args
+	return err
+}
+
+// entity 返回 T 的一级指针，用于获取子查询的表模型
+func (s *SelectSQL[T]) entity() any {
+	return new(T)
+}
+
+// session 返回执行语句的会话
+func (s *SelectSQL[T]) session() Session {
+	return s.sess
+}
+
+// As 把当前语句作为子查询使用
+// Go中的使用：From(sub.As("t")) 或者 Fields(sub.As("cnt"))
+// SQL中的使用：FROM (SELECT ...) AS `t`
+func (s *SelectSQL[T]) As(alias string) Subquery {
+	return Subquery{q: s, alias: alias}
+}
+
+// build 构建 SELECT 语句，不包括结尾的分号，因为子查询也要用
+func (s *SelectSQL[T]) build() error {
 	// 获取表模型
 	var err error
-	s.model, err = s.db.manager.Get(new(T))
+	s.model, err = s.sess.getCore().manager.Get(new(T))
 	if err != nil {
-		return nil, err
+		return err
+	}
+	// 构建 WITH 子句
+	if err = s.buildWith(s.ctes); err != nil {
+		return err
+	}
+	s.sb.WriteString("SELECT ")
+	if s.distinct {
+		s.sb.WriteString("DISTINCT ")
 	}
 	// TODO 构建查询字段
 	if err = s.buildColumns(); err != nil {
-		return nil, err
+		return err
 	}
 	s.sb.WriteString(" FROM ")
 	// 构建表名
-	s.sb.WriteByte('`')
-	s.sb.WriteString(s.model.TableName)
-	s.sb.WriteByte('`')
+	if s.table == nil {
+		s.quote(s.model.TableName)
+	} else if err = s.buildTable(s.table); err != nil {
+		return err
+	}
 
 	// 构建 WHERE 子句
-	if err = s.buildWhere(); err != nil {
-		return nil, err
+	if err = s.buildWhere(s.where); err != nil {
+		return err
 	}
-	s.sb.WriteByte(';')
-	res := &SQLInfo{SQL: s.sb.String(), Args: s.args}
-	return res, nil
+	// 构建 GROUP BY 子句
+	if err = s.buildGroupBy(); err != nil {
+		return err
+	}
+	// 构建 HAVING 子句
+	if err = s.buildHaving(); err != nil {
+		return err
+	}
+	// 构建 ORDER BY 子句
+	if err = s.buildOrderBy(); err != nil {
+		return err
+	}
+	// 构建 LIMIT 和 OFFSET 子句，不同的数据库语法不一样，交给方言处理
+	s.dialect.BuildLimit(sqlBuilder{b: s.builder}, s.limit, s.offset)
+	return nil
 }
 
 // NewSelectSQL 初始化SELECT语句对象
-func NewSelectSQL[T any](db *DB, valuer valuer.FactoryValuer) *SelectSQL[T] {
+func NewSelectSQL[T any](sess Session, valuer valuer.FactoryValuer) *SelectSQL[T] {
+	// 没有指定的时候用 DB 上默认的映射字段接口
+	if valuer == nil {
+		valuer = sess.getCore().valuer
+	}
 	return &SelectSQL[T]{
 		// sb:   &strings.Builder{},
 		// args: []any{},
-		builder: newBuilder(),
-		db:      db,
+		builder: newBuilder(sess),
+		sess:    sess,
 		valuer:  valuer,
 	}
 }
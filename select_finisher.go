@@ -0,0 +1,93 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+)
+
+// 查询语句的一些便捷的结束方法，都不会修改原来的语句
+
+// clone 复制一份查询语句，修改复制出来的语句不会影响原来的语句
+// 注意：只有重新赋值的字段是安全的，不要在复制出来的语句上 append
+func (s *SelectSQL[T]) clone() *SelectSQL[T] {
+	c := *s
+	return &c
+}
+
+// Count 查询满足条件的数据个数，会把查询列改成 COUNT(*)，保留 WHERE 条件
+// 有 DISTINCT、GROUP BY、HAVING、LIMIT 或者 OFFSET 的时候，直接改查询列结果就不对了，所以会作为子查询
+// 比如只有 HAVING 的时候，直接改查询列，HAVING 不满足就一行数据都没有了
+// SQL中的使用：
+// 1. SELECT COUNT(*) FROM `user` WHERE (`age` > ?);
+// 2. SELECT COUNT(*) FROM (SELECT `age` FROM `user` GROUP BY `age`) AS `t`;
+func (s *SelectSQL[T]) Count(ctx context.Context) (int64, error) {
+	q := s.clone()
+	q.orderBy = nil
+	if q.distinct || len(q.groupBy) > 0 || len(q.having) > 0 || q.limit > 0 || q.offset > 0 {
+		q = NewSelectSQL[T](s.sess, s.valuer).From(q.As("t"))
+	}
+	q.fields = []Selectable{CountAll()}
+	return NewSelectScalar[int64](q).QueryRawWithContext(ctx)
+}
+
+// Exists 是否存在满足条件的数据，只会查询一条数据
+func (s *SelectSQL[T]) Exists(ctx context.Context) (bool, error) {
+	q := s.clone()
+	q.orderBy = nil
+	q.limit = 1
+	sqlInfo, err := q.Build()
+	if err != nil {
+		return false, err
+	}
+	res, err := q.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return false, err
+	}
+	defer res.Close()
+	return res.Next(), res.Err()
+}
+
+// First 按照 keys 升序排列之后的第一条数据
+// 没有传入 keys 的时候，按照主键排序
+// Go中的使用：NewSelectSQL[User](db, valuer.NewUnsafeValuer).Where(F("Age").GT(18)).First(ctx)
+// SQL中的使用：SELECT * FROM `user` WHERE (`age` > ?) ORDER BY `id` ASC LIMIT ?;
+func (s *SelectSQL[T]) First(ctx context.Context, keys ...string) (*T, error) {
+	return s.firstOrLast(ctx, Asc, keys)
+}
+
+// Last 按照 keys 降序排列之后的第一条数据
+// 没有传入 keys 的时候，按照主键排序
+func (s *SelectSQL[T]) Last(ctx context.Context, keys ...string) (*T, error) {
+	return s.firstOrLast(ctx, Desc, keys)
+}
+
+func (s *SelectSQL[T]) firstOrLast(ctx context.Context, order func(fieldName string) Order, keys []string) (*T, error) {
+	if len(keys) <= 0 {
+		pks, err := primaryKeys[T](s.sess)
+		if err != nil {
+			return nil, err
+		}
+		for _, pk := range pks {
+			keys = append(keys, pk.FieldName)
+		}
+	}
+	q := s.clone()
+	q.orderBy = make([]Order, 0, len(keys))
+	for _, key := range keys {
+		q.orderBy = append(q.orderBy, order(key))
+	}
+	return q.QueryRawWithContext(ctx)
+}
+
+// Pluck 查询某一列的所有数据，V 是这一列在 Go 中的类型
+// 因为 Go 的方法不支持范型，所以这里是一个函数
+// Go中的使用：Pluck[User, string](ctx, NewSelectSQL[User](db, nil).Where(F("Age").GT(18)), "FirstName")
+// SQL中的使用：SELECT `first_name` FROM `user` WHERE (`age` > ?);
+func Pluck[T any, V any](ctx context.Context, s *SelectSQL[T], fieldName string) ([]V, error) {
+	if fieldName == "" {
+		return nil, errs.ErrNoFieldName
+	}
+	q := s.clone()
+	q.fields = []Selectable{F(fieldName)}
+	return NewSelectScalar[V](q).QueryWithContext(ctx)
+}
@@ -0,0 +1,82 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/model"
+)
+
+var _ Querier[any] = &SelectInto[any, any]{}
+
+// SelectInto 投影查询，用 T 的表模型构建语句，但是把结果扫描到 R 中
+// R 一般是一个 DTO，按照 R 的列名（或者 column 标签）映射结果集中的列
+// Go中的使用：
+// type AgeStat struct { Age uint8; Cnt int64 }
+// NewSelectInto[User, AgeStat](NewSelectSQL[User](db, valuer.NewUnsafeValuer).Fields(F("Age"), CountAll().As("cnt")).GroupBy("Age"))
+// SQL中的使用：SELECT `age`, COUNT(*) AS `cnt` FROM `user` GROUP BY `age`;
+type SelectInto[T any, R any] struct {
+	// s 具体的查询语句，执行的会话和映射字段接口都用它的
+	s *SelectSQL[T]
+	// model R 的表模型
+	model *model.Model
+}
+
+// NewSelectInto 初始化投影查询
+func NewSelectInto[T any, R any](s *SelectSQL[T]) *SelectInto[T, R] {
+	return &SelectInto[T, R]{s: s}
+}
+
+// Build 构建SQL语句，同时解析 R 的表模型
+// R 不需要提前注册，Manager 冻结之后也可以使用
+func (s *SelectInto[T, R]) Build() (*SQLInfo, error) {
+	var err error
+	s.model, err = s.s.sess.getCore().manager.GetResult(new(R))
+	if err != nil {
+		return nil, err
+	}
+	return s.s.Build()
+}
+
+// QueryWithContext 查询多条数据
+func (s *SelectInto[T, R]) QueryWithContext(ctx context.Context) ([]*R, error) {
+	sqlInfo, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.s.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	tps := make([]*R, 0)
+	for res.Next() {
+		tp := new(R)
+		val := s.s.valuer(s.model, tp)
+		err = val.SetField(res)
+		if err != nil {
+			return nil, err
+		}
+		tps = append(tps, tp)
+	}
+	return tps, nil
+}
+
+// QueryRawWithContext 查询单条数据
+func (s *SelectInto[T, R]) QueryRawWithContext(ctx context.Context) (*R, error) {
+	sqlInfo, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.s.sess.queryContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+	if !res.Next() {
+		return nil, errs.ErrNoRows
+	}
+	tp := new(R)
+	val := s.s.valuer(s.model, tp)
+	err = val.SetField(res)
+	return tp, err
+}
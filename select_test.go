@@ -87,6 +87,11 @@ func TestSelectSQL_Build(t *testing.T) {
 			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Avg("")).Where(F("Id").EQ(12)),
 			wantErr: errs.ErrNoFieldName,
 		},
+		{
+			name:    "test distinct common column",
+			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Id"), Common("Age").Distinct()),
+			wantErr: errs.ErrDistinctWithoutAggregate,
+		},
 		{
 			name: "test alias field name",
 			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Avg("Age").As("test_model_age_avg")).Where(F("Id").EQ(12)),
@@ -103,6 +108,264 @@ func TestSelectSQL_Build(t *testing.T) {
 				Args: []any{12},
 			},
 		},
+		{
+			name: "test distinct",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Distinct().Fields(F("Age"), F("FirstName")).Where(F("Id").GT(12)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT DISTINCT `age`, `first_name` FROM `test_model` WHERE (`id` > ?);",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test count all",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(F("Age"), CountAll().As("cnt")).GroupBy("Age").Having(CountAll().GT(2)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age`, COUNT(*) AS `cnt` FROM `test_model` GROUP BY `age` HAVING (COUNT(*) > ?);",
+				Args: []any{2},
+			},
+		},
+		{
+			name: "test count distinct",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Count("Age").Distinct().As("cnt"), Sum("Age").Distinct()),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT COUNT(DISTINCT `age`) AS `cnt`, SUM(DISTINCT `age`) FROM `test_model`;",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test order by",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").GT(12)).OrderBy(Asc("Age"), Desc("Id")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` > ?) ORDER BY `age` ASC, `id` DESC;",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test order by diy column name",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).OrderBy(Desc("LastName")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` ORDER BY `test_model_last_name` DESC;",
+				Args: []any{},
+			},
+		},
+		{
+			name:    "test order by invalid field",
+			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).OrderBy(Asc("Invalid")),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name: "test limit and offset",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").GT(12)).OrderBy(Asc("Id")).Limit(10).Offset(20),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` > ?) ORDER BY `id` ASC LIMIT ? OFFSET ?;",
+				Args: []any{12, 10, 20},
+			},
+		},
+		{
+			name: "test only limit",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Limit(10),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` LIMIT ?;",
+				Args: []any{10},
+			},
+		},
+		{
+			name: "test only offset",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Offset(20),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` LIMIT -1 OFFSET ?;",
+				Args: []any{20},
+			},
+		},
+		{
+			name: "test group by",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Age"), Count("Id")).GroupBy("Age", "LastName"),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age`, COUNT(`id`) FROM `test_model` GROUP BY `age`, `test_model_last_name`;",
+				Args: []any{},
+			},
+		},
+		{
+			name:    "test group by invalid field",
+			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).GroupBy("Invalid"),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name: "test having",
+			s: NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Age"), Avg("Id")).
+				Where(F("Id").GT(12)).GroupBy("Age").Having(Avg("Id").GT(30)).OrderBy(Asc("Age")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age`, AVG(`id`) FROM `test_model` WHERE (`id` > ?) GROUP BY `age` HAVING (AVG(`id`) > ?) ORDER BY `age` ASC;",
+				Args: []any{12, 30},
+			},
+		},
+		{
+			name: "test multiple having",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Age")).GroupBy("Age").Having(Count("Id").GTE(2), Max("Id").LT(100)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age` FROM `test_model` GROUP BY `age` HAVING (COUNT(`id`) >= ?) AND (MAX(`id`) < ?);",
+				Args: []any{2, 100},
+			},
+		},
+		{
+			name: "test having with common field",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Age")).GroupBy("Age").Having(Common("Age").EQ(18)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age` FROM `test_model` GROUP BY `age` HAVING (`age` = ?);",
+				Args: []any{18},
+			},
+		},
+		{
+			name:    "test having invalid field",
+			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).GroupBy("Age").Having(Sum("Invalid").GT(1)),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name: "test NEQ",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").NEQ(12)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` != ?);",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test IN",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").In(1, 2, 3)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` IN (?, ?, ?));",
+				Args: []any{1, 2, 3},
+			},
+		},
+		{
+			name: "test IN with slice",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").In([]int{1, 2}), F("Age").GT(18)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` IN (?, ?)) AND (`age` > ?);",
+				Args: []any{1, 2, 18},
+			},
+		},
+		{
+			name: "test IN with bytes",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("FirstName").In([]byte("Neo"))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`first_name` IN (?));",
+				Args: []any{[]byte("Neo")},
+			},
+		},
+		{
+			name:    "test empty IN",
+			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").In()),
+			wantErr: errs.ErrNoInValues,
+		},
+		{
+			name: "test NOT IN",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").NotIn(1, 2)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` NOT IN (?, ?));",
+				Args: []any{1, 2},
+			},
+		},
+		{
+			name: "test LIKE and NOT LIKE",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("FirstName").Like("Ne%"), F("LastName").NotLike("%o")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`first_name` LIKE ?) AND (`test_model_last_name` NOT LIKE ?);",
+				Args: []any{"Ne%", "%o"},
+			},
+		},
+		{
+			name: "test BETWEEN",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Age").Between(18, 30)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` BETWEEN ? AND ?);",
+				Args: []any{18, 30},
+			},
+		},
+		{
+			name: "test IS NULL and IS NOT NULL",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("LastName").IsNull().OR(F("FirstName").IsNotNull())),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`test_model_last_name` IS NULL) OR (`first_name` IS NOT NULL);",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test column to column",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Age").GT(F("Id")), F("FirstName").EQ("Neo")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` > `id`) AND (`first_name` = ?);",
+				Args: []any{"Neo"},
+			},
+		},
+		{
+			name: "test arithmetic on left",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Age").Add(1).Mul(2).LT(F("Id").Sub(F("Age").Div(2)))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE ((`age` + ?) * ? < `id` - `age` / ?);",
+				Args: []any{1, 2, 2},
+			},
+		},
+		{
+			name: "test arithmetic with right grouping",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Age").Sub(F("Id").Sub(1)).GTE(0)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` - (`id` - ?) >= ?);",
+				Args: []any{1, 0},
+			},
+		},
+		{
+			name: "test having compare aggregates",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Age")).GroupBy("Age").Having(Max("Id").GT(Avg("Id"))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age` FROM `test_model` GROUP BY `age` HAVING (MAX(`id`) > AVG(`id`));",
+				Args: []any{},
+			},
+		},
+		{
+			name:    "test invalid right column",
+			s:       NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Age").GT(F("Invalid"))),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name: "test OR inside AND",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").EQ(1).OR(F("Id").EQ(2)), F("Age").GT(18)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE ((`id` = ?) OR (`id` = ?)) AND (`age` > ?);",
+				Args: []any{1, 2, 18},
+			},
+		},
+		{
+			name: "test AND inside OR",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").EQ(1).AND(F("Age").GT(18)).OR(F("Id").EQ(2))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` = ?) AND (`age` > ?) OR (`id` = ?);",
+				Args: []any{1, 18, 2},
+			},
+		},
+		{
+			name: "test NOT with AND",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(NOT(F("Id").EQ(1).AND(F("Age").GT(18)))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE NOT ((`id` = ?) AND (`age` > ?));",
+				Args: []any{1, 18},
+			},
+		},
+		{
+			name: "test nested NOT",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(NOT(NOT(F("Id").EQ(1))).OR(NOT(F("Age").In(1, 2)))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE NOT NOT (`id` = ?) OR NOT (`age` IN (?, ?));",
+				Args: []any{1, 1, 2},
+			},
+		},
+		{
+			name: "test OR inside having",
+			s:    NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(Common("Age")).GroupBy("Age").Having(Count("Id").GT(1).OR(Max("Id").LT(10)), Avg("Id").GT(5)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `age` FROM `test_model` GROUP BY `age` HAVING ((COUNT(`id`) > ?) OR (MAX(`id`) < ?)) AND (AVG(`id`) > ?);",
+				Args: []any{1, 10, 5},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -266,3 +529,667 @@ func TestSelectSQL_QueryWithContext(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectSQL_Join(t *testing.T) {
+	db := memoryDB(t)
+	o := TableOf[TestOrder]().As("o")
+	u := TableOf[TestModel]().As("u")
+	testCases := []struct {
+		name    string
+		s       *SelectSQL[TestOrder]
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test from table with alias",
+			s:    NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(o).Where(o.F("Id").GT(12)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_order` AS `o` WHERE (`o`.`id` > ?);",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test table without alias",
+			s:    NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(TableOf[TestOrder]()).Where(TableOf[TestOrder]().F("Id").GT(12)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_order` WHERE (`test_order`.`id` > ?);",
+				Args: []any{12},
+			},
+		},
+		{
+			name: "test join",
+			s: NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).
+				Fields(o.F("Id"), o.F("Amount"), u.F("FirstName").As("user_name")).
+				From(o.Join(u).On(o.F("UserId").EQ(u.F("Id")))).
+				Where(u.F("Age").GT(18)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `o`.`id`, `o`.`amount`, `u`.`first_name` AS `user_name` FROM `test_order` AS `o` JOIN `test_model` AS `u` ON (`o`.`user_id` = `u`.`id`) WHERE (`u`.`age` > ?);",
+				Args: []any{18},
+			},
+		},
+		{
+			name: "test left join with multiple conditions",
+			s: NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).
+				From(o.LeftJoin(u).On(o.F("UserId").EQ(u.F("Id")), u.F("Age").GT(18))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_order` AS `o` LEFT JOIN `test_model` AS `u` ON (`o`.`user_id` = `u`.`id`) AND (`u`.`age` > ?);",
+				Args: []any{18},
+			},
+		},
+		{
+			name: "test right join with using",
+			s:    NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(o.RightJoin(u).Using("Id")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_order` AS `o` RIGHT JOIN `test_model` AS `u` USING (`id`);",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test join chain",
+			s: NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).
+				From(o.Join(u).On(o.F("UserId").EQ(u.F("Id"))).LeftJoin(TableOf[TestModelV1]().As("v")).On(u.F("Id").EQ(TableOf[TestModelV1]().As("v").F("Id")))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_order` AS `o` JOIN `test_model` AS `u` ON (`o`.`user_id` = `u`.`id`) LEFT JOIN `db_test_model` AS `v` ON (`u`.`id` = `v`.`id`);",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test join on the right",
+			s: NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).
+				From(o.Join(u.Join(TableOf[TestModelV1]().As("v")).Using("Id")).On(o.F("UserId").EQ(u.F("Id")))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_order` AS `o` JOIN (`test_model` AS `u` JOIN `db_test_model` AS `v` USING (`id`)) ON (`o`.`user_id` = `u`.`id`);",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test join with group by and order by of joined table",
+			s: NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).
+				Fields(u.F("FirstName"), Sum("Amount").As("total")).
+				From(o.Join(u).On(o.F("UserId").EQ(u.F("Id")))).
+				GroupByFields(u.F("FirstName")).OrderBy(u.F("FirstName").Desc(), o.F("UserId").Asc()),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `u`.`first_name`, SUM(`amount`) AS `total` FROM `test_order` AS `o` JOIN `test_model` AS `u` ON (`o`.`user_id` = `u`.`id`) GROUP BY `u`.`first_name` ORDER BY `u`.`first_name` DESC, `o`.`user_id` ASC;",
+				Args: []any{},
+			},
+		},
+		{
+			name:    "test invalid group by field of joined table",
+			s:       NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(o.Join(u).On(o.F("UserId").EQ(u.F("Id")))).GroupByFields(u.F("Amount")),
+			wantErr: errs.NewErrNotSupportUnknownField("Amount"),
+		},
+		{
+			name:    "test invalid order by field of joined table",
+			s:       NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(o.Join(u).On(o.F("UserId").EQ(u.F("Id")))).OrderBy(u.F("Amount").Asc()),
+			wantErr: errs.NewErrNotSupportUnknownField("Amount"),
+		},
+		{
+			name:    "test invalid field of joined table",
+			s:       NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(o.Join(u).On(o.F("UserId").EQ(u.F("Amount")))),
+			wantErr: errs.NewErrNotSupportUnknownField("Amount"),
+		},
+		{
+			name:    "test invalid using field",
+			s:       NewSelectSQL[TestOrder](db, valuer.NewUnsafeValuer).From(o.Join(u).Using("Invalid")),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestSelectSQL_Subquery(t *testing.T) {
+	db := memoryDB(t)
+	sub := NewSelectSQL[TestOrder](db, nil).Fields(F("UserId")).Where(F("Amount").GT(100))
+	testCases := []struct {
+		name    string
+		s       *SelectSQL[TestModel]
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test in query",
+			s:    NewSelectSQL[TestModel](db, nil).Where(F("Age").GT(18), F("Id").InQuery(sub), F("FirstName").EQ("Neo")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` > ?) AND (`id` IN (SELECT `user_id` FROM `test_order` WHERE (`amount` > ?))) AND (`first_name` = ?);",
+				Args: []any{18, 100, "Neo"},
+			},
+		},
+		{
+			name: "test not in query",
+			s:    NewSelectSQL[TestModel](db, nil).Where(F("Id").NotInQuery(sub)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` NOT IN (SELECT `user_id` FROM `test_order` WHERE (`amount` > ?)));",
+				Args: []any{100},
+			},
+		},
+		{
+			name: "test exists",
+			s: NewSelectSQL[TestModel](db, nil).Where(F("Age").GT(18).AND(Exists(NewSelectSQL[TestOrder](db, nil).
+				From(TableOf[TestOrder]().As("o")).Where(TableOf[TestOrder]().As("o").F("UserId").EQ(TableOf[TestModel]().F("Id")))))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` > ?) AND EXISTS (SELECT * FROM `test_order` AS `o` WHERE (`o`.`user_id` = `test_model`.`id`));",
+				Args: []any{18},
+			},
+		},
+		{
+			name: "test not exists",
+			s:    NewSelectSQL[TestModel](db, nil).Where(NotExists(sub)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE NOT EXISTS (SELECT `user_id` FROM `test_order` WHERE (`amount` > ?));",
+				Args: []any{100},
+			},
+		},
+		{
+			name: "test compare with subquery",
+			s:    NewSelectSQL[TestModel](db, nil).Where(F("Age").GT(NewSelectSQL[TestModel](db, nil).Fields(Avg("Age")).Where(F("Id").LT(10)))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` > (SELECT AVG(`age`) FROM `test_model` WHERE (`id` < ?)));",
+				Args: []any{10},
+			},
+		},
+		{
+			name: "test from subquery",
+			s: NewSelectSQL[TestModel](db, nil).Fields(sub.As("t").F("UserId")).
+				From(sub.As("t")).Where(sub.As("t").F("UserId").GT(12)).Limit(10),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `t`.`user_id` FROM (SELECT `user_id` FROM `test_order` WHERE (`amount` > ?)) AS `t` WHERE (`t`.`user_id` > ?) LIMIT ?;",
+				Args: []any{100, 12, 10},
+			},
+		},
+		{
+			name: "test join subquery",
+			s: NewSelectSQL[TestModel](db, nil).Fields(F("Id")).
+				From(TableOf[TestModel]().Join(sub.As("t")).On(TableOf[TestModel]().F("Id").EQ(sub.As("t").F("UserId")))).
+				Where(F("Age").GT(18)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `id` FROM `test_model` JOIN (SELECT `user_id` FROM `test_order` WHERE (`amount` > ?)) AS `t` ON (`test_model`.`id` = `t`.`user_id`) WHERE (`age` > ?);",
+				Args: []any{100, 18},
+			},
+		},
+		{
+			name: "test subquery in select list",
+			s: NewSelectSQL[TestModel](db, nil).
+				Fields(F("Id"), NewSelectSQL[TestOrder](db, nil).Fields(Count("Id")).Where(F("Amount").GT(100)).As("cnt")).
+				Where(F("Age").GT(18)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `id`, (SELECT COUNT(`id`) FROM `test_order` WHERE (`amount` > ?)) AS `cnt` FROM `test_model` WHERE (`age` > ?);",
+				Args: []any{100, 18},
+			},
+		},
+		{
+			name:    "test from subquery without alias",
+			s:       NewSelectSQL[TestModel](db, nil).From(sub.As("")),
+			wantErr: errs.ErrSubqueryNoAlias,
+		},
+		{
+			name:    "test invalid field in subquery",
+			s:       NewSelectSQL[TestModel](db, nil).Where(F("Id").InQuery(NewSelectSQL[TestOrder](db, nil).Fields(F("Age")))),
+			wantErr: errs.NewErrNotSupportUnknownField("Age"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+
+	// 子查询被使用之后，自己单独构建也不受影响
+	res, err := sub.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &SQLInfo{
+		SQL:  "SELECT `user_id` FROM `test_order` WHERE (`amount` > ?);",
+		Args: []any{100},
+	}, res)
+}
+
+type TestEmployee struct {
+	Id        int64
+	ManagerId int64
+	Name      string
+}
+
+func TestSelectSQL_CTE(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("cte", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_employee` (`id` INTEGER PRIMARY KEY, `manager_id` INTEGER, `name` TEXT);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_employee` VALUES (1, 0, 'CEO'), (2, 1, 'CTO'), (3, 2, 'Dev'), (4, 0, 'Other'), (5, 3, 'Intern');")
+	assert.NoError(t, err)
+
+	e := TableOf[TestEmployee]().As("e")
+	tree := CTE[TestEmployee]("tree")
+	recursive := func(id int64) *SelectSQL[TestEmployee] {
+		anchor := NewSelectSQL[TestEmployee](db, nil).Where(F("Id").EQ(id))
+		next := NewSelectSQL[TestEmployee](db, nil).Fields(e.F("Id"), e.F("ManagerId"), e.F("Name")).
+			From(e.Join(tree).On(e.F("ManagerId").EQ(tree.F("Id"))))
+		return NewSelectSQL[TestEmployee](db, valuer.NewUnsafeValuer).WithRecursive("tree", anchor, next).From(tree)
+	}
+
+	testCases := []struct {
+		name    string
+		s       *SelectSQL[TestEmployee]
+		wantSQL *SQLInfo
+		wantRes []*TestEmployee
+	}{
+		{
+			name: "test with",
+			s: NewSelectSQL[TestEmployee](db, valuer.NewUnsafeValuer).
+				With("boss", NewSelectSQL[TestEmployee](db, nil).Where(F("ManagerId").EQ(0))).
+				From(CTE[TestEmployee]("boss")).Where(F("Id").GT(1)),
+			wantSQL: &SQLInfo{
+				SQL:  "WITH `boss` AS (SELECT * FROM `test_employee` WHERE (`manager_id` = ?)) SELECT * FROM `boss` WHERE (`id` > ?);",
+				Args: []any{0, 1},
+			},
+			wantRes: []*TestEmployee{{Id: 4, ManagerId: 0, Name: "Other"}},
+		},
+		{
+			name: "test with recursive",
+			s:    recursive(2).OrderBy(Asc("Id")),
+			wantSQL: &SQLInfo{
+				SQL: "WITH RECURSIVE `tree` AS (SELECT * FROM `test_employee` WHERE (`id` = ?) UNION ALL " +
+					"SELECT `e`.`id`, `e`.`manager_id`, `e`.`name` FROM `test_employee` AS `e` JOIN `tree` ON (`e`.`manager_id` = `tree`.`id`)) " +
+					"SELECT * FROM `tree` ORDER BY `id` ASC;",
+				Args: []any{int64(2)},
+			},
+			wantRes: []*TestEmployee{
+				{Id: 2, ManagerId: 1, Name: "CTO"},
+				{Id: 3, ManagerId: 2, Name: "Dev"},
+				{Id: 5, ManagerId: 3, Name: "Intern"},
+			},
+		},
+		{
+			name: "test multiple ctes",
+			s: recursive(1).With("other", NewSelectSQL[TestEmployee](db, nil).Where(F("Name").EQ("Other"))).
+				Where(F("Id").NotInQuery(NewSelectSQL[TestEmployee](db, nil).Fields(F("Id")).From(CTE[TestEmployee]("other")))).
+				OrderBy(Desc("Id")).Limit(2),
+			wantSQL: &SQLInfo{
+				SQL: "WITH RECURSIVE `tree` AS (SELECT * FROM `test_employee` WHERE (`id` = ?) UNION ALL " +
+					"SELECT `e`.`id`, `e`.`manager_id`, `e`.`name` FROM `test_employee` AS `e` JOIN `tree` ON (`e`.`manager_id` = `tree`.`id`)), " +
+					"`other` AS (SELECT * FROM `test_employee` WHERE (`name` = ?)) " +
+					"SELECT * FROM `tree` WHERE (`id` NOT IN (SELECT `id` FROM `other`)) ORDER BY `id` DESC LIMIT ?;",
+				Args: []any{int64(1), "Other", 2},
+			},
+			wantRes: []*TestEmployee{
+				{Id: 5, ManagerId: 3, Name: "Intern"},
+				{Id: 3, ManagerId: 2, Name: "Dev"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlInfo, err := tc.s.Build()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, sqlInfo)
+			res, err := tc.s.QueryWithContext(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+type TestOrder struct {
+	Id     int64
+	UserId int64
+	Amount int
+}
+
+func TestSelectSQL_QueryNested(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+
+	o := TableOf[TestOrder]().As("o")
+	u := TableOf[TestModel]().As("u")
+	s := func(v valuer.FactoryValuer) *SelectSQL[TestOrderWithUser] {
+		return NewSelectSQL[TestOrderWithUser](db, v).
+			Fields(o.F("Id"), o.F("Amount"), u.F("Id").As("u__id"), u.F("FirstName").As("u__first_name"),
+				u.F("LastName").As("buyer__test_model_last_name")).
+			From(o.Join(u).On(o.F("UserId").EQ(u.F("Id"))))
+	}
+	wantSQL := "SELECT `o`.`id`, `o`.`amount`, `u`.`id` AS `u__id`, `u`.`first_name` AS `u__first_name`, `u`.`test_model_last_name` AS `buyer__test_model_last_name` FROM `test_order` AS `o` JOIN `test_model` AS `u` ON (`o`.`user_id` = `u`.`id`);"
+	res, err := s(valuer.NewUnsafeValuer).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, wantSQL, res.SQL)
+
+	testCases := []struct {
+		name       string
+		s          *SelectSQL[TestOrderWithUser]
+		prepareSQL func()
+		wantRes    []*TestOrderWithUser
+		wantErr    error
+	}{
+		{
+			name: "test unsafe valuer",
+			s:    s(valuer.NewUnsafeValuer),
+			prepareSQL: func() {
+				mockRes := sqlmock.NewRows([]string{"id", "amount", "u__id", "u__first_name", "buyer__test_model_last_name"})
+				mockRes.AddRow(1, 100, 12, "JASON", "Neo")
+				mockRes.AddRow(2, 200, 13, "Tank", "Alice")
+				mock.ExpectQuery("SELECT .*").WillReturnRows(mockRes)
+			},
+			wantRes: []*TestOrderWithUser{
+				{
+					Id: 1, Amount: 100,
+					User:  TestModel{Id: 12, FirstName: "JASON"},
+					Buyer: &TestModel{LastName: &sql.NullString{Valid: true, String: "Neo"}},
+				},
+				{
+					Id: 2, Amount: 200,
+					User:  TestModel{Id: 13, FirstName: "Tank"},
+					Buyer: &TestModel{LastName: &sql.NullString{Valid: true, String: "Alice"}},
+				},
+			},
+		},
+		{
+			name: "test reflect valuer",
+			s:    s(valuer.NewReflectValuer),
+			prepareSQL: func() {
+				mockRes := sqlmock.NewRows([]string{"id", "amount", "u__id", "u__first_name", "buyer__test_model_last_name"})
+				mockRes.AddRow(1, 100, 12, "JASON", "Neo")
+				mock.ExpectQuery("SELECT .*").WillReturnRows(mockRes)
+			},
+			wantRes: []*TestOrderWithUser{
+				{
+					Id: 1, Amount: 100,
+					User:  TestModel{Id: 12, FirstName: "JASON"},
+					Buyer: &TestModel{LastName: &sql.NullString{Valid: true, String: "Neo"}},
+				},
+			},
+		},
+		{
+			name: "test unsafe valuer left join without match",
+			s:    s(valuer.NewUnsafeValuer),
+			prepareSQL: func() {
+				mockRes := sqlmock.NewRows([]string{"id", "amount", "u__id", "u__first_name", "buyer__id", "buyer__test_model_last_name"})
+				mockRes.AddRow(1, 100, 12, "JASON", nil, nil)
+				mockRes.AddRow(2, 200, 13, "Tank", 7, nil)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(mockRes)
+			},
+			// 嵌套结构体指针的列都是 NULL 的时候，结构体指针是 nil
+			wantRes: []*TestOrderWithUser{
+				{Id: 1, Amount: 100, User: TestModel{Id: 12, FirstName: "JASON"}},
+				{Id: 2, Amount: 200, User: TestModel{Id: 13, FirstName: "Tank"}, Buyer: &TestModel{Id: 7}},
+			},
+		},
+		{
+			name: "test reflect valuer left join without match",
+			s:    s(valuer.NewReflectValuer),
+			prepareSQL: func() {
+				mockRes := sqlmock.NewRows([]string{"id", "amount", "u__id", "u__first_name", "buyer__id", "buyer__test_model_last_name"})
+				mockRes.AddRow(1, 100, 12, "JASON", nil, nil)
+				mockRes.AddRow(2, 200, 13, "Tank", 7, nil)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(mockRes)
+			},
+			// 嵌套结构体指针的列都是 NULL 的时候，结构体指针是 nil
+			wantRes: []*TestOrderWithUser{
+				{Id: 1, Amount: 100, User: TestModel{Id: 12, FirstName: "JASON"}},
+				{Id: 2, Amount: 200, User: TestModel{Id: 13, FirstName: "Tank"}, Buyer: &TestModel{Id: 7}},
+			},
+		},
+		{
+			name: "test unknown nested column",
+			s:    s(valuer.NewReflectValuer),
+			prepareSQL: func() {
+				mockRes := sqlmock.NewRows([]string{"id", "u__invalid"})
+				mockRes.AddRow(1, 100)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(mockRes)
+			},
+			wantErr: errs.NewErrNotSupportUnknownColumn("u__invalid"),
+		},
+		{
+			name: "test not nested column",
+			s:    s(valuer.NewUnsafeValuer),
+			prepareSQL: func() {
+				mockRes := sqlmock.NewRows([]string{"id", "amount__id"})
+				mockRes.AddRow(1, 100)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(mockRes)
+			},
+			wantErr: errs.NewErrNotSupportUnknownColumn("amount__id"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.prepareSQL()
+			res, err := tc.s.QueryWithContext(ctx)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+type TestOrderWithUser struct {
+	Id     int64
+	Amount int
+	User   TestModel `orm:"column=u"`
+	Buyer  *TestModel
+}
+
+func TestSelectScalar(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("scalar", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_model` (`id`, `first_name`, `age`) VALUES (1, 'Tom', 10), (2, 'Neo', 30), (3, 'Jason', 30);")
+	assert.NoError(t, err)
+
+	avg, err := NewSelectScalar[float64](NewSelectSQL[TestModel](db, nil).Fields(Avg("Age").As("avg_age"))).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(70)/3, avg)
+
+	cnt, err := NewSelectScalar[int64](NewSelectSQL[TestModel](db, nil).Fields(CountAll()).Where(F("Age").EQ(30))).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cnt)
+
+	// 没有数据的时候聚合函数返回 NULL
+	maxAge, err := NewSelectScalar[sql.NullInt64](NewSelectSQL[TestModel](db, nil).Fields(Max("Age")).Where(F("Age").GT(100))).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, sql.NullInt64{}, maxAge)
+
+	names, err := NewSelectScalar[string](NewSelectSQL[TestModel](db, nil).Fields(F("FirstName")).OrderBy(Desc("Id"))).QueryWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Jason", "Neo", "Tom"}, names)
+
+	ages, err := NewSelectScalar[int](NewSelectSQL[TestModel](db, nil).Distinct().Fields(F("Age")).
+		Union(NewSelectSQL[TestModel](db, nil).Fields(F("Id"))).OrderBy(Asc("Age"))).QueryWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 10, 30}, ages)
+
+	_, err = NewSelectScalar[string](NewSelectSQL[TestModel](db, nil).Fields(F("FirstName")).Where(F("Id").GT(100))).QueryRawWithContext(ctx)
+	assert.Equal(t, errs.ErrNoRows, err)
+
+	_, err = NewSelectScalar[string](NewSelectSQL[TestModel](db, nil).Fields(F("Invalid"))).QueryWithContext(ctx)
+	assert.Equal(t, errs.NewErrNotSupportUnknownField("Invalid"), err)
+}
+
+type TestAgeStat struct {
+	Age    uint8
+	Cnt    int64
+	MaxId  int64 `orm:"column=max_id"`
+	Oldest string
+}
+
+func TestSelectInto(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("select_into", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_model` (`id`, `first_name`, `age`) VALUES (1, 'Tom', 10), (2, 'Neo', 30), (3, 'Jason', 30);")
+	assert.NoError(t, err)
+
+	s := func(v valuer.FactoryValuer) *SelectSQL[TestModel] {
+		return NewSelectSQL[TestModel](db, v).Fields(F("Age"), CountAll().As("cnt"), Max("Id").As("max_id")).
+			GroupBy("Age").OrderBy(Asc("Age"))
+	}
+	testCases := []struct {
+		name    string
+		q       Querier[TestAgeStat]
+		wantRes []*TestAgeStat
+		wantErr error
+	}{
+		{
+			name: "test unsafe valuer",
+			q:    NewSelectInto[TestModel, TestAgeStat](s(valuer.NewUnsafeValuer)),
+			wantRes: []*TestAgeStat{
+				{Age: 10, Cnt: 1, MaxId: 1},
+				{Age: 30, Cnt: 2, MaxId: 3},
+			},
+		},
+		{
+			name: "test reflect valuer",
+			q:    NewSelectInto[TestModel, TestAgeStat](s(valuer.NewReflectValuer)),
+			wantRes: []*TestAgeStat{
+				{Age: 10, Cnt: 1, MaxId: 1},
+				{Age: 30, Cnt: 2, MaxId: 3},
+			},
+		},
+		{
+			name:    "test unknown column",
+			q:       NewSelectInto[TestModel, TestAgeStat](NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(F("Id"))),
+			wantErr: errs.NewErrNotSupportUnknownColumn("id"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.q.QueryWithContext(ctx)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+
+	res, err := NewSelectInto[TestModel, TestAgeStat](NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).
+		Fields(F("FirstName").As("oldest"), F("Age")).OrderBy(Desc("Age"), Desc("Id"))).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestAgeStat{Age: 30, Oldest: "Jason"}, res)
+}
+
+func TestSelectSQL_Finisher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("finisher", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_model` (`id`, `first_name`, `age`) VALUES (1, 'Tom', 10), (2, 'Neo', 30), (3, 'Jason', 30), (4, 'Alice', 20);")
+	assert.NoError(t, err)
+
+	s := func() *SelectSQL[TestModel] {
+		return NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(F("Id"), F("FirstName"), F("Age"))
+	}
+
+	// Count
+	cnt, err := s().Where(F("Age").GTE(20)).OrderBy(Asc("Age")).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), cnt)
+	cnt, err = NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(F("Age")).GroupBy("Age").Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), cnt)
+	cnt, err = s().Distinct().Fields(F("Age")).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), cnt)
+	cnt, err = s().Limit(2).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cnt)
+	// 只有 HAVING 没有 GROUP BY 的时候，整个表是一组
+	cnt, err = NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(CountAll()).Having(CountAll().GT(100)).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+	cnt, err = NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Fields(CountAll()).Having(CountAll().GT(1)).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), cnt)
+	_, err = s().Where(F("Invalid").EQ(1)).Count(ctx)
+	assert.Equal(t, errs.NewErrNotSupportUnknownField("Invalid"), err)
+
+	// Exists
+	ok, err := s().Where(F("Age").GT(20)).Exists(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = s().Where(F("Age").GT(100)).Exists(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// First 和 Last
+	res, err := s().Where(F("Age").GTE(20)).First(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 2, FirstName: "Neo", Age: 30}, res)
+	res, err = s().Where(F("Age").GTE(20)).Last(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 4, FirstName: "Alice", Age: 20}, res)
+	res, err = s().Last(ctx, "Age", "Id")
+	assert.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 3, FirstName: "Jason", Age: 30}, res)
+	_, err = s().Where(F("Age").GT(100)).First(ctx)
+	assert.Equal(t, errs.ErrNoRows, err)
+	_, err = s().First(ctx, "Invalid")
+	assert.Equal(t, errs.NewErrNotSupportUnknownField("Invalid"), err)
+
+	// Pluck
+	names, err := Pluck[TestModel, string](ctx, s().Where(F("Age").EQ(30)).OrderBy(Desc("Id")), "FirstName")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Jason", "Neo"}, names)
+	_, err = Pluck[TestModel, string](ctx, s(), "")
+	assert.Equal(t, errs.ErrNoFieldName, err)
+
+	// 原来的语句不受影响
+	q := s().Where(F("Age").EQ(30)).OrderBy(Desc("Id"))
+	_, err = q.First(ctx)
+	assert.NoError(t, err)
+	_, err = q.Count(ctx)
+	assert.NoError(t, err)
+	sqlInfo, err := q.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `id`, `first_name`, `age` FROM `test_model` WHERE (`age` = ?) ORDER BY `id` DESC;", sqlInfo.SQL)
+}
+
+func TestSelectSQL_QueryRawWithLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE \\(`id` > \\?\\) LIMIT \\?;").
+		WithArgs(12, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(13))
+	s := NewSelectSQL[TestModel](db, valuer.NewUnsafeValuer).Where(F("Id").GT(12))
+	res, err := s.QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestModel{Id: 13}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 原来的语句不受影响
+	sqlInfo, err := s.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `test_model` WHERE (`id` > ?);", sqlInfo.SQL)
+}
@@ -0,0 +1,31 @@
+package orm_framework
+
+import (
+	"context"
+	"database/sql"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/borntodie-new/orm-framework/model"
+)
+
+// Session 执行 SQL 语句的抽象，DB 和 Tx 都实现了这个接口
+// 所有的 New*SQL 方法接收的都是 Session，所以同一条语句既可以直接在 DB 上执行，也可以放到事务中执行
+// 注意：这里的方法都是私有的，意味着用户没办法自己实现 Session
+type Session interface {
+	// getCore 获取构建 SQL 需要的公共组件
+	getCore() core
+	// queryContext 执行查询语句
+	queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	// execContext 执行增删改语句
+	execContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// core DB 和 Tx 共享的组件
+// 事务是从 DB 上开启的，所以 Tx 直接复用 DB 的 core 就可以了
+type core struct {
+	// manager model 管理器
+	manager *model.Manager
+	// dialect 方言
+	dialect Dialect
+	// valuer 默认的映射字段接口，语句没有指定的时候使用
+	valuer valuer.FactoryValuer
+}
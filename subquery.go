@@ -0,0 +1,71 @@
+package orm_framework
+
+// Subquery 子查询
+// 子查询既可以出现在 WHERE 子句中，也可以出现在 FROM 子句和 SELECT 列表中
+// Go中的使用：
+// 1. F("Id").InQuery(NewSelectSQL[Order](db, nil).Fields(F("UserId")))
+// 2. From(NewSelectSQL[Order](db, nil).As("o"))
+// SQL中的使用：
+// 1. WHERE (`id` IN (SELECT `user_id` FROM `order`))
+// 2. FROM (SELECT * FROM `order`) AS `o`
+type Subquery struct {
+	// q 子查询的语句
+	q QueryBuilder
+	// alias 子查询的别名，在 FROM 子句和 SELECT 列表中使用
+	alias string
+}
+
+var (
+	_ Expression     = Subquery{}
+	_ TableReference = Subquery{}
+	_ Selectable     = Subquery{}
+)
+
+// expr 标记位
+func (s Subquery) expr() {}
+
+// selectable 标记位
+func (s Subquery) selectable() {}
+
+func (s Subquery) tableAlias() string {
+	return s.alias
+}
+
+// F 初始化一个属于子查询的 Field
+// 构建的时候会用子查询的 model 解析字段，并且带上子查询的别名，比如 `t`.`user_id`
+func (s Subquery) F(fieldName string) Field {
+	return Field{
+		table:     s,
+		fieldName: fieldName,
+	}
+}
+
+func (s Subquery) Join(right TableReference) JoinBuilder {
+	return JoinBuilder{left: s, right: right, typ: "JOIN"}
+}
+
+func (s Subquery) LeftJoin(right TableReference) JoinBuilder {
+	return JoinBuilder{left: s, right: right, typ: "LEFT JOIN"}
+}
+
+func (s Subquery) RightJoin(right TableReference) JoinBuilder {
+	return JoinBuilder{left: s, right: right, typ: "RIGHT JOIN"}
+}
+
+// Exists 实现SQL中的 EXISTS 语句
+// Go中的使用：Exists(NewSelectSQL[Order](db, nil).Where(F("UserId").EQ(12)))
+// SQL中的使用：WHERE EXISTS (SELECT * FROM `order` WHERE (`user_id` = ?))
+func Exists(q QueryBuilder) Predicate {
+	return Predicate{
+		op:    EXISTSType,
+		right: Subquery{q: q},
+	}
+}
+
+// NotExists 实现SQL中的 NOT EXISTS 语句
+func NotExists(q QueryBuilder) Predicate {
+	return Predicate{
+		op:    NOTEXISTSType,
+		right: Subquery{q: q},
+	}
+}
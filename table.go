@@ -0,0 +1,129 @@
+package orm_framework
+
+import "github.com/borntodie-new/orm-framework/model"
+
+// TableReference 表的抽象，可以是普通的表，也可以是 JOIN 查询
+// Go中的使用：TableOf[Order]().As("o").Join(TableOf[User]().As("u")).On(o.F("UserId").EQ(u.F("Id")))
+// SQL中的使用：`order` AS `o` JOIN `user` AS `u` ON (`o`.`user_id` = `u`.`id`)
+type TableReference interface {
+	// tableAlias 表的别名
+	tableAlias() string
+}
+
+var _ TableReference = Table{}
+
+// Table 普通的表
+type Table struct {
+	// entity 表模型，是一个一级指针，用于获取 model
+	entity any
+	// alias 表的别名
+	alias string
+	// name 表名，为空的时候用 model 中的表名，引用 CTE 的时候就是 CTE 的名字
+	name string
+}
+
+// TableOf 初始化一个普通的表
+func TableOf[T any]() Table {
+	return Table{entity: new(T)}
+}
+
+func (t Table) tableAlias() string {
+	return t.alias
+}
+
+// tableName 表名，没有指定的时候用 model 中的表名
+func (t Table) tableName(m *model.Model) string {
+	if t.name != "" {
+		return t.name
+	}
+	return m.TableName
+}
+
+// As 设置表的别名
+func (t Table) As(alias string) Table {
+	t.alias = alias
+	return t
+}
+
+// F 初始化一个属于当前表的 Field
+// 构建的时候会用当前表的 model 解析字段，并且带上表的别名，比如 `u`.`name`
+func (t Table) F(fieldName string) Field {
+	return Field{
+		table:     t,
+		fieldName: fieldName,
+	}
+}
+
+func (t Table) Join(right TableReference) JoinBuilder {
+	return JoinBuilder{left: t, right: right, typ: "JOIN"}
+}
+
+func (t Table) LeftJoin(right TableReference) JoinBuilder {
+	return JoinBuilder{left: t, right: right, typ: "LEFT JOIN"}
+}
+
+func (t Table) RightJoin(right TableReference) JoinBuilder {
+	return JoinBuilder{left: t, right: right, typ: "RIGHT JOIN"}
+}
+
+var _ TableReference = Join{}
+
+// Join JOIN 查询
+type Join struct {
+	// left JOIN 的左边
+	left TableReference
+	// right JOIN 的右边
+	right TableReference
+	// typ JOIN 的类型，JOIN、LEFT JOIN 或者 RIGHT JOIN
+	typ string
+	// on ON 条件
+	on []Predicate
+	// using USING 的字段，Go中的字段名
+	using []string
+}
+
+// tableAlias JOIN 查询是没有别名的
+func (j Join) tableAlias() string {
+	return ""
+}
+
+func (j Join) Join(right TableReference) JoinBuilder {
+	return JoinBuilder{left: j, right: right, typ: "JOIN"}
+}
+
+func (j Join) LeftJoin(right TableReference) JoinBuilder {
+	return JoinBuilder{left: j, right: right, typ: "LEFT JOIN"}
+}
+
+func (j Join) RightJoin(right TableReference) JoinBuilder {
+	return JoinBuilder{left: j, right: right, typ: "RIGHT JOIN"}
+}
+
+// JoinBuilder 构造 Join 的中间结构
+// 为什么需要这个结构？因为 JOIN 必须要有 ON 或者 USING，这样就强制用户调用 On 或者 Using 了
+type JoinBuilder struct {
+	left  TableReference
+	right TableReference
+	typ   string
+}
+
+// On 设置 JOIN 的条件，多个条件之间用 AND 连接
+func (j JoinBuilder) On(condition ...Predicate) Join {
+	return Join{
+		left:  j.left,
+		right: j.right,
+		typ:   j.typ,
+		on:    condition,
+	}
+}
+
+// Using 设置 JOIN 的 USING 字段，注意这里是 Go 中的字段名
+// 会用左边的表来解析字段名
+func (j JoinBuilder) Using(fieldNames ...string) Join {
+	return Join{
+		left:  j.left,
+		right: j.right,
+		typ:   j.typ,
+		using: fieldNames,
+	}
+}
@@ -0,0 +1,153 @@
+package orm_framework
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+)
+
+var _ Session = &Tx{}
+
+// Tx 事务
+// 通过 DB.BeginTx 开启，所有的 New*SQL 方法都可以传入 Tx，这样语句就会在事务中执行
+// 在 Tx 上再调用 BeginTx 开启的是嵌套事务，嵌套事务是通过 SAVEPOINT 实现的：
+// 1. 开启嵌套事务 => SAVEPOINT sp_1
+// 2. 提交嵌套事务 => RELEASE SAVEPOINT sp_1
+// 3. 回滚嵌套事务 => ROLLBACK TO SAVEPOINT sp_1
+// 所以嵌套事务回滚只会撤销它自己的修改，外层事务还可以继续执行
+type Tx struct {
+	core
+	// tx 真实的事务对象，嵌套事务和外层事务共用同一个
+	tx *sql.Tx
+	// savepoint 嵌套事务对应的保存点名字，最外层的事务为空
+	savepoint string
+	// savepointSeq 保存点的序号，同一个事务中的所有嵌套事务共享，保证保存点名字不会重复
+	savepointSeq *int
+	// done 事务是否已经提交或者回滚了
+	done bool
+	// parent 外层事务，最外层的事务为 nil
+	// 外层事务结束之后，保存点就不存在了，嵌套事务也就跟着结束了
+	parent *Tx
+	// ctx 开启嵌套事务时传入的 context，提交和回滚保存点的时候使用
+	// 和 sql.Tx 一样，context 在开启事务的时候就绑定了
+	ctx context.Context
+}
+
+// isDone 事务或者任意一层外层事务已经提交或者回滚了
+func (t *Tx) isDone() bool {
+	for tx := t; tx != nil; tx = tx.parent {
+		if tx.done {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tx) getCore() core {
+	return t.core
+}
+
+// queryContext 执行查询语句
+// 嵌套事务和外层事务共用同一个 sql.Tx，所以嵌套事务结束之后需要自己拦截，否则语句会继续在外层事务中执行
+func (t *Tx) queryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if t.isDone() {
+		return nil, sql.ErrTxDone
+	}
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+// execContext 执行增删改语句，和 queryContext 一样，嵌套事务结束之后不能再执行
+func (t *Tx) execContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if t.isDone() {
+		return nil, sql.ErrTxDone
+	}
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+// BeginTx 开启嵌套事务
+func (t *Tx) BeginTx(ctx context.Context) (*Tx, error) {
+	if t.isDone() {
+		return nil, sql.ErrTxDone
+	}
+	*t.savepointSeq++
+	savepoint := fmt.Sprintf("sp_%d", *t.savepointSeq)
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+savepoint+";"); err != nil {
+		return nil, err
+	}
+	return &Tx{
+		core:         t.core,
+		tx:           t.tx,
+		savepoint:    savepoint,
+		savepointSeq: t.savepointSeq,
+		parent:       t,
+		ctx:          ctx,
+	}, nil
+}
+
+// DoTx 在嵌套事务中执行 fn，规则和 DB.DoTx 一样
+// fn 返回 error 或者 panic 只会回滚到嵌套事务开启的地方
+func (t *Tx) DoTx(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := t.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	return doTx(tx, fn)
+}
+
+// Commit 提交事务
+func (t *Tx) Commit() error {
+	if t.savepoint == "" {
+		t.done = true
+		return t.tx.Commit()
+	}
+	if t.isDone() {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.tx.ExecContext(t.ctx, "RELEASE SAVEPOINT "+t.savepoint+";")
+	return err
+}
+
+// Rollback 回滚事务
+func (t *Tx) Rollback() error {
+	if t.savepoint == "" {
+		t.done = true
+		return t.tx.Rollback()
+	}
+	if t.isDone() {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.tx.ExecContext(t.ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint+";")
+	return err
+}
+
+// RollbackIfNotCommit 如果事务还没有提交就回滚
+// 一般配合 defer 使用，事务已经提交的情况下回滚会返回 sql.ErrTxDone，这里直接忽略掉
+func (t *Tx) RollbackIfNotCommit() error {
+	err := t.Rollback()
+	if err == sql.ErrTxDone {
+		return nil
+	}
+	return err
+}
+
+// doTx 执行 fn，fn 返回 nil 就提交事务，返回 error 或者发生 panic 就回滚事务
+// 发生 panic 的情况下，回滚之后会把 panic 继续往上抛
+func doTx(tx *Tx, fn func(tx *Tx) error) (err error) {
+	panicked := true
+	defer func() {
+		if panicked || err != nil {
+			rbErr := tx.Rollback()
+			if rbErr != nil {
+				err = errs.NewErrFailedToRollbackTx(err, rbErr, panicked)
+			}
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	err = fn(tx)
+	panicked = false
+	return err
+}
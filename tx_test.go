@@ -0,0 +1,268 @@
+package orm_framework
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTx_CommitAndRollback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+
+	t.Run("test commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `test_model` SET .*").WillReturnResult(driver.RowsAffected(1))
+		mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+		mock.ExpectCommit()
+
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		res, err := NewUpdateSQL[TestModel](tx).Values("FirstName", "Neo").Where(F("Id").EQ(12)).ExecuteWithContext(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, res.err)
+		tm, err := NewSelectSQL[TestModel](tx, valuer.NewUnsafeValuer).Fields(Common("Id")).QueryRawWithContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &TestModel{Id: 12}, tm)
+		assert.NoError(t, tx.Commit())
+		// 已经提交的事务不需要再回滚
+		assert.NoError(t, tx.RollbackIfNotCommit())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("test rollback", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM .*").WillReturnResult(driver.RowsAffected(1))
+		mock.ExpectRollback()
+
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		_, err = NewDeleteSQL[TestModel](tx).Where(F("Id").EQ(12)).ExecuteWithContext(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.RollbackIfNotCommit())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDB_DoTx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		prepareSQL func()
+		fn         func(tx *Tx) error
+		wantErr    error
+		wantPanic  bool
+	}{
+		{
+			name: "test commit",
+			prepareSQL: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO .*").WillReturnResult(driver.RowsAffected(1))
+				mock.ExpectCommit()
+			},
+			fn: func(tx *Tx) error {
+				res, err := NewInsertSQL[TestModel](tx).Values(&TestModel{Id: 1}).ExecuteWithContext(ctx)
+				if err != nil {
+					return err
+				}
+				_, err = res.RowsAffected()
+				return err
+			},
+		},
+		{
+			name: "test rollback with error",
+			prepareSQL: func() {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(tx *Tx) error {
+				return errors.New("biz error")
+			},
+			wantErr: errors.New("biz error"),
+		},
+		{
+			name: "test rollback with panic",
+			prepareSQL: func() {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			fn: func(tx *Tx) error {
+				panic("biz panic")
+			},
+			wantPanic: true,
+		},
+		{
+			name: "test begin error",
+			prepareSQL: func() {
+				mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+			},
+			fn: func(tx *Tx) error {
+				return nil
+			},
+			wantErr: errors.New("begin error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.prepareSQL()
+			if tc.wantPanic {
+				assert.Panics(t, func() {
+					_ = db.DoTx(ctx, tc.fn)
+				})
+			} else {
+				err := db.DoTx(ctx, tc.fn)
+				assert.Equal(t, tc.wantErr, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTx_NestedTx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("nested_tx", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+
+	err = db.DoTx(ctx, func(tx *Tx) error {
+		if _, err := NewInsertSQL[TestModel](tx).Values(&TestModel{Id: 1, FirstName: "outer"}).ExecuteWithContext(ctx); err != nil {
+			return err
+		}
+		// 嵌套事务失败，只回滚嵌套事务自己的修改
+		err := tx.DoTx(ctx, func(tx *Tx) error {
+			if _, err := NewInsertSQL[TestModel](tx).Values(&TestModel{Id: 2, FirstName: "inner"}).ExecuteWithContext(ctx); err != nil {
+				return err
+			}
+			return errors.New("inner error")
+		})
+		assert.Equal(t, errors.New("inner error"), err)
+		// 嵌套事务成功，修改保留在外层事务中
+		return tx.DoTx(ctx, func(tx *Tx) error {
+			_, err := NewInsertSQL[TestModel](tx).Values(&TestModel{Id: 3, FirstName: "inner"}).ExecuteWithContext(ctx)
+			return err
+		})
+	})
+	assert.NoError(t, err)
+
+	res, err := NewSelectSQL[TestModel](db, valuer.NewReflectValuer).Fields(Common("Id"), Common("FirstName")).QueryWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestModel{{Id: 1, FirstName: "outer"}, {Id: 3, FirstName: "inner"}}, res)
+}
+
+func TestTx_NestedTxSQL(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	assert.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1;").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1;").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectExec("SAVEPOINT sp_2;").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_2;").WillReturnResult(driver.ResultNoRows)
+	mock.ExpectCommit()
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	inner, err := tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, inner.Commit())
+	assert.Equal(t, sql.ErrTxDone, inner.Commit())
+	inner, err = tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, inner.RollbackIfNotCommit())
+	assert.NoError(t, inner.RollbackIfNotCommit())
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTx_NestedTxParentDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("nested_tx_done", t)
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+
+	// 外层的嵌套事务提交之后，里层的嵌套事务就结束了
+	parent, err := tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	child, err := parent.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, parent.Commit())
+	assert.Equal(t, sql.ErrTxDone, child.Commit())
+	assert.Equal(t, sql.ErrTxDone, child.Rollback())
+	_, err = child.BeginTx(ctx)
+	assert.Equal(t, sql.ErrTxDone, err)
+
+	// 外层的嵌套事务回滚之后，里层的嵌套事务也结束了
+	parent, err = tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	child, err = parent.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, parent.Rollback())
+	assert.Equal(t, sql.ErrTxDone, child.Rollback())
+	assert.NoError(t, child.RollbackIfNotCommit())
+
+	// 最外层的事务提交之后，所有的嵌套事务都结束了
+	child, err = tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, sql.ErrTxDone, child.Commit())
+}
+
+func TestTx_FinishedNestedTxStatement(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("nested_tx_statement", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_model` (`id` INTEGER PRIMARY KEY, `first_name` TEXT, `age` INTEGER, `test_model_last_name` TEXT);")
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	// 回滚之后的嵌套事务不能再执行语句，否则语句会在外层事务中执行
+	inner, err := tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, inner.Rollback())
+	res, err := NewInsertSQL[TestModel](inner).Values(&TestModel{Id: 1, FirstName: "inner"}).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, sql.ErrTxDone, res.err)
+	_, err = NewSelectSQL[TestModel](inner, nil).QueryWithContext(ctx)
+	assert.Equal(t, sql.ErrTxDone, err)
+	// 提交之后的嵌套事务也一样
+	inner, err = tx.BeginTx(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, inner.Commit())
+	res, err = NewInsertSQL[TestModel](inner).Values(&TestModel{Id: 2, FirstName: "inner"}).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, sql.ErrTxDone, res.err)
+	assert.NoError(t, tx.Commit())
+
+	cnt, err := NewSelectSQL[TestModel](db, nil).Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+}
@@ -19,6 +19,11 @@ type Executer interface {
 	ExecuteWithContext(ctx context.Context) (*Result, error)
 }
 
+// Selectable 可以出现在 SELECT 列表中的元素，比如普通的列、聚合函数
+type Selectable interface {
+	selectable()
+}
+
 // Builder 构建SQL语句的的接口
 type Builder interface {
 	// Build 构建SQL语句
@@ -32,3 +37,15 @@ type SQLInfo struct {
 	// Args 具体的SQL参数
 	Args []any
 }
+
+// QueryBuilder 可以作为子查询的语句，目前只有 SELECT 语句
+type QueryBuilder interface {
+	Builder
+	// buildSubquery 把语句构建到 parent 中，不包括结尾的分号
+	// 子查询的参数会按照顺序追加到 parent 的参数中
+	buildSubquery(parent *builder) error
+	// entity 返回语句对应的表模型，是一个一级指针
+	entity() any
+	// session 返回执行语句的会话
+	session() Session
+}
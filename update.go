@@ -15,10 +15,11 @@ type UpdateSQL[T any] struct {
 	where []Predicate
 	// args SQL语句中的参数
 	// args []any
-	// db 全局的、自定义的数据库连接对象
-	db *DB
+	// sess 执行语句的会话，可以是 DB 也可以是 Tx
+	sess Session
 	// values 需要修改的数据
-	values map[string]any
+	// 注意：这里不能用 map，map 的遍历顺序是随机的，生成的 SQL 语句就不稳定了
+	values []Assignment
 	// model 维护 T 的表模型结构
 	// model *model.Model
 	// builder 抽象出新的 SQL 构造器
@@ -30,100 +31,19 @@ func (u *UpdateSQL[T]) Where(condition ...Predicate) *UpdateSQL[T] {
 	return u
 }
 
+// Values 设置需要修改的字段
+// data 可以是普通的值，也可以是 Expression，比如 Values("Stock", F("Stock").Sub(1))
 func (u *UpdateSQL[T]) Values(fieldName string, data any) *UpdateSQL[T] {
-	if u.values == nil {
-		u.values = make(map[string]any)
-	}
-	u.values[fieldName] = data
+	u.values = append(u.values, Assign(fieldName, data))
 	return u
 }
 
-// addArgs 添加SQL参数
-func (u *UpdateSQL[T]) addArgs(val any) {
-	if val == nil {
-		return
-	}
-	u.args = append(u.args, val)
-}
-
-// buildWhere 构建 WHERE 语句
-func (u *UpdateSQL[T]) buildWhere() error {
-	if len(u.where) <= 0 {
-		return nil
-	}
-	u.sb.WriteString(" WHERE ")
-	p := u.where[0]
-	for i := 1; i < len(u.where)-1; i++ {
-		p = p.AND(u.where[i])
-	}
-	return u.buildFields(p)
-}
-
-// buildFields 构建WHERE语句
-func (u *UpdateSQL[T]) buildFields(exp Expression) error {
-	switch typ := exp.(type) {
-	case nil:
-		return nil
-	case Field:
-		// 这是纯字段
-		// 注意 Field传入的是Go中的字段名，设置到SQL上的是SQL中的列名
-		u.sb.WriteByte('(')
-		u.sb.WriteByte('`')
-		fd, ok := u.model.FieldsMap[typ.fieldName]
-		if !ok {
-			return errs.NewErrNotSupportUnknownField(typ.fieldName)
-		}
-		u.sb.WriteString(fd.ColumnName)
-		u.sb.WriteByte('`')
-	case Predicate:
-		// 这里需要递归实现，因为是 Predicate 类型，可能是 Field 也可能是 Value
-
-		// 构建左边
-		if err := u.buildFields(typ.left); err != nil {
-			return err
-		}
-		// 构建操作类型
-		u.sb.WriteString(typ.op.String())
-		// 构建右边
-		if err := u.buildFields(typ.right); err != nil {
-			return err
-		}
-	case Value:
-		// 这里是字段值
-		u.sb.WriteString("?")
-		u.addArgs(typ.val)
-		u.sb.WriteByte(')')
-	default:
-		return errs.ErrNotSupportPredicate
-	}
-	return nil
-}
-
 // buildValues 构建 赋值 子句
 func (u *UpdateSQL[T]) buildValues() error {
 	if len(u.values) <= 0 {
 		return errs.ErrNotUpdateSQLSetClause
 	}
-	idx := 0
-	for fieldName, value := range u.values {
-		if idx > 0 {
-			u.sb.WriteString(", ")
-		}
-		fd, ok := u.model.FieldsMap[fieldName]
-		if !ok {
-			return errs.NewErrNotSupportUnknownField(fieldName)
-		}
-		// 设置列名
-		u.sb.WriteByte('`')
-		u.sb.WriteString(fd.ColumnName)
-		u.sb.WriteByte('`')
-		// 设置占位符
-		u.sb.WriteString(" = ?")
-		// 保存数据
-		u.addArgs(value)
-		idx++
-	}
-	return nil
+	return u.buildAssignments(u.values)
 }
 
 // ExecuteWithContext 执行SQL语句
@@ -132,7 +52,7 @@ func (u *UpdateSQL[T]) ExecuteWithContext(ctx context.Context) (*Result, error)
 	if err != nil {
 		return nil, err
 	}
-	res, err := u.db.db.ExecContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
+	res, err := u.sess.execContext(ctx, sqlInfo.SQL, sqlInfo.Args...)
 	if err != nil {
 		return &Result{
 			err: err,
@@ -145,24 +65,23 @@ func (u *UpdateSQL[T]) ExecuteWithContext(ctx context.Context) (*Result, error)
 // Build 构造SQL语句和维护SQL参数
 // UPDATE `test_model` SET `first_name` = 'Fred' WHERE `id` = 1;
 func (u *UpdateSQL[T]) Build() (*SQLInfo, error) {
+	u.builder = newBuilder(u.sess)
 	// 构建SQL基本架构
 	u.sb.WriteString("UPDATE ")
 	var err error
-	u.model, err = u.db.manager.Get(new(T))
+	u.model, err = u.sess.getCore().manager.Get(new(T))
 	if err != nil {
 		return nil, err
 	}
 	// 构建表名
-	u.sb.WriteByte('`')
-	u.sb.WriteString(u.model.TableName)
-	u.sb.WriteByte('`')
+	u.quote(u.model.TableName)
 	u.sb.WriteString(" SET ")
 	// TODO 构建赋值子句
 	if err = u.buildValues(); err != nil {
 		return nil, err
 	}
 	// 构建WHERE语句
-	if err = u.buildWhere(); err != nil {
+	if err = u.buildWhere(u.where); err != nil {
 		return nil, err
 	}
 	u.sb.WriteByte(';')
@@ -170,11 +89,11 @@ func (u *UpdateSQL[T]) Build() (*SQLInfo, error) {
 	return res, nil
 }
 
-func NewUpdateSQL[T any](db *DB) *UpdateSQL[T] {
+func NewUpdateSQL[T any](sess Session) *UpdateSQL[T] {
 	return &UpdateSQL[T]{
 		// sb:   &strings.Builder{},
 		// args: []any{},
-		builder: newBuilder(),
-		db:      db,
+		builder: newBuilder(sess),
+		sess:    sess,
 	}
 }
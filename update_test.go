@@ -51,6 +51,67 @@ func TestUpdateSQL_Build(t *testing.T) {
 				Args: []any{1, "Neo", 12},
 			},
 		},
+		{
+			name: "test update with IN",
+			u:    NewUpdateSQL[TestModel](db).Values("Age", 18).Where(F("Id").In(1, 2)),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `age` = ? WHERE (`id` IN (?, ?));",
+				Args: []any{18, 1, 2},
+			},
+		},
+		{
+			name: "test update with IS NULL",
+			u:    NewUpdateSQL[TestModel](db).Values("Age", 18).Where(F("LastName").IsNull()),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `age` = ? WHERE (`test_model_last_name` IS NULL);",
+				Args: []any{18},
+			},
+		},
+		{
+			name: "test update with BETWEEN",
+			u:    NewUpdateSQL[TestModel](db).Values("Age", 18).Where(F("Id").Between(1, 10)),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `age` = ? WHERE (`id` BETWEEN ? AND ?);",
+				Args: []any{18, 1, 10},
+			},
+		},
+		{
+			name: "test set with arithmetic",
+			u:    NewUpdateSQL[TestModel](db).Values("Age", F("Age").Sub(1)).Where(F("Id").EQ(12)),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `age` = `age` - ? WHERE (`id` = ?);",
+				Args: []any{1, 12},
+			},
+		},
+		{
+			name: "test set with column",
+			u:    NewUpdateSQL[TestModel](db).Values("FirstName", F("LastName")).Values("Age", 18).Where(F("Age").LT(F("Id"))),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `first_name` = `test_model_last_name`, `age` = ? WHERE (`age` < `id`);",
+				Args: []any{18},
+			},
+		},
+		{
+			name:    "test set with invalid column",
+			u:       NewUpdateSQL[TestModel](db).Values("Age", F("Invalid").Add(1)),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name: "test update with multiple where",
+			u:    NewUpdateSQL[TestModel](db).Values("Age", 18).Where(F("Id").GT(1), F("Id").LT(10), F("FirstName").EQ("Neo")),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `age` = ? WHERE (`id` > ?) AND (`id` < ?) AND (`first_name` = ?);",
+				Args: []any{18, 1, 10, "Neo"},
+			},
+		},
+		{
+			name: "test update with OR inside AND",
+			u:    NewUpdateSQL[TestModel](db).Values("Age", 18).Where(F("Id").EQ(1).OR(F("Id").EQ(2))).Where(NOT(F("LastName").IsNull())),
+			wantRes: &SQLInfo{
+				SQL:  "UPDATE `test_model` SET `age` = ? WHERE ((`id` = ?) OR (`id` = ?)) AND NOT (`test_model_last_name` IS NULL);",
+				Args: []any{18, 1, 2},
+			},
+		},
 		{
 			name:    "test unknown field",
 			u:       NewUpdateSQL[TestModel](db).Values("Invalid", 1),
@@ -76,6 +137,10 @@ func TestUpdateSQL_Build(t *testing.T) {
 				return
 			}
 			assert.Equal(t, tc.wantRes, res)
+			// 多次构建的结果是一样的
+			res, err = tc.u.Build()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
 		})
 	}
 }
@@ -0,0 +1,64 @@
+package orm_framework
+
+// Upsert 插入冲突的时候怎么处理，方言根据它构建 upsert 子句
+// MySQL 中的使用：INSERT INTO ... ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)
+// SQLite 和 PostgreSQL 中的使用：INSERT INTO ... ON CONFLICT (`email`) DO UPDATE SET `name` = excluded.`name`
+type Upsert struct {
+	// ConflictFields 冲突的字段，Go中的字段名
+	// MySQL 是根据所有的唯一索引判断冲突的，所以不需要
+	ConflictFields []string
+	// Assigns 冲突的时候更新的值
+	Assigns []Assignment
+	// DoNothing 冲突的时候什么都不做
+	DoNothing bool
+}
+
+// UpsertBuilder 构造 upsert 的中间结构
+// 为什么需要这个结构？因为 OnConflict 之后必须要指定怎么处理冲突，这样就强制用户调用 DoUpdate、DoUpdateSet 或者 DoNothing 了
+type UpsertBuilder[T any] struct {
+	i              *InsertSQL[T]
+	conflictFields []string
+}
+
+// OnConflict 指定冲突的字段，注意这里是 Go 中的字段名
+// Go中的使用：NewInsertSQL[User](db).Values(&u).OnConflict("Email").DoUpdate("Name", "UpdatedAt")
+func (i *InsertSQL[T]) OnConflict(fieldNames ...string) UpsertBuilder[T] {
+	return UpsertBuilder[T]{i: i, conflictFields: fieldNames}
+}
+
+// DoUpdate 冲突的时候用插入的值更新这些字段
+func (u UpsertBuilder[T]) DoUpdate(fieldNames ...string) *InsertSQL[T] {
+	assigns := make([]Assignment, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		assigns = append(assigns, Assign(fieldName, Excluded(fieldName)))
+	}
+	return u.DoUpdateSet(assigns...)
+}
+
+// DoUpdateSet 冲突的时候按照 assigns 更新，值可以是任意的表达式
+// Go中的使用：DoUpdateSet(Assign("Count", F("Count").Add(Excluded("Count"))), Assign("Name", "Neo"))
+// SQL中的使用：`count` = `count` + excluded.`count`, `name` = ?
+func (u UpsertBuilder[T]) DoUpdateSet(assigns ...Assignment) *InsertSQL[T] {
+	u.i.upsert = &Upsert{ConflictFields: u.conflictFields, Assigns: assigns}
+	return u.i
+}
+
+// DoNothing 冲突的时候什么都不做
+func (u UpsertBuilder[T]) DoNothing() *InsertSQL[T] {
+	u.i.upsert = &Upsert{ConflictFields: u.conflictFields, DoNothing: true}
+	return u.i
+}
+
+// excludedValue 插入的时候冲突的那一行数据的值
+type excludedValue struct {
+	fieldName string
+}
+
+// expr 标记位
+func (e excludedValue) expr() {}
+
+// Excluded 引用插入的时候冲突的那一行数据的值，只能在 upsert 的更新部分使用
+// MySQL 中是 VALUES(`name`)，SQLite 和 PostgreSQL 中是 excluded.`name`
+func Excluded(fieldName string) Expression {
+	return excludedValue{fieldName: fieldName}
+}
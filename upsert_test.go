@@ -0,0 +1,170 @@
+package orm_framework
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type TestUpsertModel struct {
+	Id    int64 `orm:"pk,auto_increment"`
+	Email string
+	Name  string
+	Count int64
+}
+
+func TestInsertSQL_Upsert(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	mysql, err := OpenDB(mockDB, DBWithDialect(MySQL))
+	assert.NoError(t, err)
+	sqlite, err := OpenDB(mockDB, DBWithDialect(SQLite3))
+	assert.NoError(t, err)
+	pg, err := OpenDB(mockDB, DBWithDialect(PostgreSQL))
+	assert.NoError(t, err)
+	val := &TestUpsertModel{Email: "neo@test.com", Name: "Neo", Count: 1}
+	testCases := []struct {
+		name    string
+		i       Builder
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test mysql do update",
+			i:    NewInsertSQL[TestUpsertModel](mysql).Values(val).OnConflict("Email").DoUpdate("Name", "Count"),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `count` = VALUES(`count`);",
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name: "test mysql do update set",
+			i: NewInsertSQL[TestUpsertModel](mysql).Values(val).OnConflict().
+				DoUpdateSet(Assign("Count", F("Count").Add(Excluded("Count"))), Assign("Name", "Jason")),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `count` = `count` + VALUES(`count`), `name` = ?;",
+				Args: []any{"neo@test.com", "Neo", int64(1), "Jason"},
+			},
+		},
+		{
+			name: "test mysql do nothing",
+			i:    NewInsertSQL[TestUpsertModel](mysql).Values(val).OnConflict("Email").DoNothing(),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `email` = `email`;",
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name: "test mysql do nothing without conflict fields",
+			i:    NewInsertSQL[TestUpsertModel](mysql).Values(val).OnConflict().DoNothing(),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `id` = `id`;",
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name: "test sqlite do update",
+			i:    NewInsertSQL[TestUpsertModel](sqlite).Values(val).OnConflict("Email").DoUpdate("Name", "Count"),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON CONFLICT (`email`) DO UPDATE SET `name` = excluded.`name`, `count` = excluded.`count`;",
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name: "test sqlite do update set",
+			i: NewInsertSQL[TestUpsertModel](sqlite).Values(val).OnConflict("Email").
+				DoUpdateSet(Assign("Count", F("Count").Add(Excluded("Count")))),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON CONFLICT (`email`) DO UPDATE SET `count` = `test_upsert_model`.`count` + excluded.`count`;",
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name: "test sqlite do nothing without conflict fields",
+			i:    NewInsertSQL[TestUpsertModel](sqlite).Values(val).OnConflict().DoNothing(),
+			wantRes: &SQLInfo{
+				SQL:  "INSERT INTO `test_upsert_model` (`email`, `name`, `count`) VALUES (?, ?, ?) ON CONFLICT DO NOTHING;",
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name: "test postgres do update set",
+			i: NewInsertSQL[TestUpsertModel](pg).Values(val).OnConflict("Email", "Name").
+				DoUpdateSet(Assign("Count", F("Count").Add(Excluded("Count"))), Assign("Name", "Jason")),
+			wantRes: &SQLInfo{
+				SQL:  `INSERT INTO "test_upsert_model" ("email", "name", "count") VALUES ($1, $2, $3) ON CONFLICT ("email", "name") DO UPDATE SET "count" = "test_upsert_model"."count" + excluded."count", "name" = $4;`,
+				Args: []any{"neo@test.com", "Neo", int64(1), "Jason"},
+			},
+		},
+		{
+			name: "test postgres do nothing",
+			i:    NewInsertSQL[TestUpsertModel](pg).Values(val).OnConflict("Email").DoNothing(),
+			wantRes: &SQLInfo{
+				SQL:  `INSERT INTO "test_upsert_model" ("email", "name", "count") VALUES ($1, $2, $3) ON CONFLICT ("email") DO NOTHING;`,
+				Args: []any{"neo@test.com", "Neo", int64(1)},
+			},
+		},
+		{
+			name:    "test do update without conflict fields",
+			i:       NewInsertSQL[TestUpsertModel](pg).Values(val).OnConflict().DoUpdate("Name"),
+			wantErr: errs.ErrUpsertNoConflictFields,
+		},
+		{
+			name:    "test unknown conflict field",
+			i:       NewInsertSQL[TestUpsertModel](sqlite).Values(val).OnConflict("Invalid").DoNothing(),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name:    "test unknown update field",
+			i:       NewInsertSQL[TestUpsertModel](mysql).Values(val).OnConflict().DoUpdate("Invalid"),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.i.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestInsertSQL_UpsertExecute(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db, err := Open("sqlite3", "file:upsert.db?cache=shared&mode=memory", DBWithDialect(SQLite3))
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_upsert_model` (`id` INTEGER PRIMARY KEY AUTOINCREMENT, `email` TEXT UNIQUE, `name` TEXT, `count` INTEGER);")
+	assert.NoError(t, err)
+
+	_, err = NewInsertSQL[TestUpsertModel](db).Values(&TestUpsertModel{Email: "neo@test.com", Name: "Neo", Count: 1}).ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+
+	// 冲突的时候什么都不做
+	val := &TestUpsertModel{Email: "neo@test.com", Name: "Jason", Count: 10}
+	res, err := NewInsertSQL[TestUpsertModel](db).Values(val).OnConflict("Email").DoNothing().ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, res.err)
+	// upsert 的时候不回填自增 ID
+	assert.Equal(t, int64(0), val.Id)
+	found, err := NewSelectSQL[TestUpsertModel](db, nil).Where(F("Email").EQ("neo@test.com")).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestUpsertModel{Id: 1, Email: "neo@test.com", Name: "Neo", Count: 1}, found)
+
+	// 冲突的时候更新
+	res, err = NewInsertSQL[TestUpsertModel](db).Values(val).OnConflict("Email").
+		DoUpdateSet(Assign("Name", Excluded("Name")), Assign("Count", F("Count").Add(Excluded("Count")))).
+		ExecuteWithContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, res.err)
+	found, err = NewSelectSQL[TestUpsertModel](db, nil).Where(F("Email").EQ("neo@test.com")).QueryRawWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &TestUpsertModel{Id: 1, Email: "neo@test.com", Name: "Jason", Count: 11}, found)
+}
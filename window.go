@@ -0,0 +1,189 @@
+package orm_framework
+
+import (
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"strconv"
+)
+
+// 窗口函数
+// 看下窗口函数怎么用
+// SELECT `id`, ROW_NUMBER() OVER (PARTITION BY `dept_id` ORDER BY `salary` DESC) AS `rn` FROM `employee`;
+
+type WindowFn string
+
+func (w WindowFn) String() string {
+	return string(w)
+}
+
+const (
+	ROWNUMBER WindowFn = "ROW_NUMBER"
+	RANK      WindowFn = "RANK"
+	DENSERANK WindowFn = "DENSE_RANK"
+	LAG       WindowFn = "LAG"
+	LEAD      WindowFn = "LEAD"
+)
+
+// WindowFunc 专用的窗口函数，必须调用 Over 之后才能用在 SELECT 列表中
+type WindowFunc struct {
+	// fn 窗口函数名
+	fn WindowFn
+	// fieldName Go中结构体的字段名，LAG 和 LEAD 才有
+	fieldName string
+	// offset LAG 和 LEAD 的偏移量
+	offset int
+}
+
+// RowNumber 分区内的行号，从 1 开始
+func RowNumber() WindowFunc {
+	return WindowFunc{fn: ROWNUMBER}
+}
+
+// Rank 分区内的排名，相同的值排名相同，后面的排名会跳过
+func Rank() WindowFunc {
+	return WindowFunc{fn: RANK}
+}
+
+// DenseRank 分区内的排名，相同的值排名相同，后面的排名不会跳过
+func DenseRank() WindowFunc {
+	return WindowFunc{fn: DENSERANK}
+}
+
+// Lag 分区内往前 offset 行的值
+func Lag(fieldName string, offset int) WindowFunc {
+	return WindowFunc{fn: LAG, fieldName: fieldName, offset: offset}
+}
+
+// Lead 分区内往后 offset 行的值
+func Lead(fieldName string, offset int) WindowFunc {
+	return WindowFunc{fn: LEAD, fieldName: fieldName, offset: offset}
+}
+
+// Over 指定窗口
+// Go中的使用：RowNumber().Over(PartitionBy("DeptId"), OrderBy(Desc("Salary")))
+func (w WindowFunc) Over(specs ...WindowSpec) Window {
+	return newWindow(w, specs)
+}
+
+// Over 聚合函数作为窗口函数使用
+// Go中的使用：Sum("Salary").Over(PartitionBy("DeptId"))
+// SQL中的使用：SUM(`salary`) OVER (PARTITION BY `dept_id`)
+func (a Aggregate) Over(specs ...WindowSpec) Window {
+	return newWindow(a, specs)
+}
+
+// WindowSpec 窗口的定义，也就是 OVER 后面括号里面的内容
+type WindowSpec struct {
+	// partitionBy 分区字段
+	partitionBy []Field
+	// orderBy 分区内的排序条件
+	orderBy []Order
+}
+
+// PartitionBy 设置窗口的分区字段
+func PartitionBy(fieldNames ...string) WindowSpec {
+	fields := make([]Field, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		fields = append(fields, F(fieldName))
+	}
+	return WindowSpec{partitionBy: fields}
+}
+
+// PartitionByFields 设置窗口的分区字段，JOIN 查询的时候可以指定是哪张表的列
+// Go中的使用：PartitionByFields(d.F("Name"))
+// SQL中的使用：PARTITION BY `d`.`name`
+func PartitionByFields(fields ...Field) WindowSpec {
+	return WindowSpec{partitionBy: fields}
+}
+
+// OrderBy 设置窗口内的排序条件
+func OrderBy(orders ...Order) WindowSpec {
+	return WindowSpec{orderBy: orders}
+}
+
+var _ Selectable = Window{}
+
+// Window 带有 OVER 子句的窗口函数，可以用在 SELECT 列表中
+type Window struct {
+	// fn 窗口函数，WindowFunc 或者 Aggregate
+	fn any
+	// spec 合并之后的窗口定义
+	spec WindowSpec
+	// alias 列别名
+	alias string
+}
+
+func newWindow(fn any, specs []WindowSpec) Window {
+	w := Window{fn: fn}
+	for _, spec := range specs {
+		w.spec.partitionBy = append(w.spec.partitionBy, spec.partitionBy...)
+		w.spec.orderBy = append(w.spec.orderBy, spec.orderBy...)
+	}
+	return w
+}
+
+func (w Window) As(alias string) Window {
+	w.alias = alias
+	return w
+}
+
+// selectable 标记位
+func (w Window) selectable() {}
+
+// buildWindow 构建窗口函数，不包括别名
+func (b *builder) buildWindow(w Window) error {
+	switch fn := w.fn.(type) {
+	case Aggregate:
+		// 聚合函数的别名在 Window 上，这里不需要
+		if err := b.buildAggregate(fn); err != nil {
+			return err
+		}
+	case WindowFunc:
+		if err := b.buildWindowFunc(fn); err != nil {
+			return err
+		}
+	default:
+		return errs.ErrNotSupportSelectable
+	}
+	b.sb.WriteString(" OVER (")
+	for idx, field := range w.spec.partitionBy {
+		if idx > 0 {
+			b.sb.WriteString(", ")
+		} else {
+			b.sb.WriteString("PARTITION BY ")
+		}
+		if err := b.buildColumn(field); err != nil {
+			return err
+		}
+	}
+	if len(w.spec.orderBy) > 0 {
+		if len(w.spec.partitionBy) > 0 {
+			b.sb.WriteByte(' ')
+		}
+		b.sb.WriteString("ORDER BY ")
+		if err := b.buildOrders(w.spec.orderBy); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildWindowFunc 构建专用的窗口函数，比如 ROW_NUMBER()、LAG(`salary`, 1)
+// LAG 和 LEAD 的偏移量是整数，直接写在 SQL 里面，不作为参数
+func (b *builder) buildWindowFunc(fn WindowFunc) error {
+	b.sb.WriteString(fn.fn.String())
+	b.sb.WriteByte('(')
+	if fn.fieldName != "" {
+		fd, ok := b.model.FieldsMap[fn.fieldName]
+		if !ok {
+			return errs.NewErrNotSupportUnknownField(fn.fieldName)
+		}
+		b.quote(fd.ColumnName)
+		if fn.offset > 0 {
+			b.sb.WriteString(", ")
+			b.sb.WriteString(strconv.Itoa(fn.offset))
+		}
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
@@ -0,0 +1,125 @@
+package orm_framework
+
+import (
+	"context"
+	"database/sql"
+	"github.com/borntodie-new/orm-framework/internal/errs"
+	"github.com/borntodie-new/orm-framework/internal/valuer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type TestSalary struct {
+	Id     int64
+	DeptId int64
+	Salary int64
+	Rn     int64
+	Prev   sql.NullInt64
+	Total  int64
+}
+
+func TestWindow_Build(t *testing.T) {
+	db := memoryDB(t)
+	sa := TableOf[TestSalary]().As("s")
+	testCases := []struct {
+		name    string
+		s       *SelectSQL[TestSalary]
+		wantRes *SQLInfo
+		wantErr error
+	}{
+		{
+			name: "test row number",
+			s: NewSelectSQL[TestSalary](db, nil).
+				Fields(F("Id"), RowNumber().Over(PartitionBy("DeptId"), OrderBy(Desc("Salary"))).As("rn")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `id`, ROW_NUMBER() OVER (PARTITION BY `dept_id` ORDER BY `salary` DESC) AS `rn` FROM `test_salary`;",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test rank and dense rank",
+			s: NewSelectSQL[TestSalary](db, nil).
+				Fields(Rank().Over(OrderBy(Desc("Salary"), Asc("Id"))), DenseRank().Over(PartitionBy("DeptId", "Id"))),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT RANK() OVER (ORDER BY `salary` DESC, `id` ASC), DENSE_RANK() OVER (PARTITION BY `dept_id`, `id`) FROM `test_salary`;",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test lag and lead",
+			s: NewSelectSQL[TestSalary](db, nil).
+				Fields(Lag("Salary", 1).Over(OrderBy(Asc("Id"))).As("prev"), Lead("Salary", 2).Over()).
+				Where(F("DeptId").EQ(1)),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT LAG(`salary`, 1) OVER (ORDER BY `id` ASC) AS `prev`, LEAD(`salary`, 2) OVER () FROM `test_salary` WHERE (`dept_id` = ?);",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "test aggregate over",
+			s:    NewSelectSQL[TestSalary](db, nil).Fields(F("Id"), Sum("Salary").Over(PartitionBy("DeptId")).As("total")),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `id`, SUM(`salary`) OVER (PARTITION BY `dept_id`) AS `total` FROM `test_salary`;",
+				Args: []any{},
+			},
+		},
+		{
+			name: "test partition by field of joined table",
+			s: NewSelectSQL[TestSalary](db, nil).
+				Fields(sa.F("Id"), RowNumber().Over(PartitionByFields(sa.F("DeptId")), OrderBy(sa.F("Salary").Desc())).As("rn")).
+				From(sa),
+			wantRes: &SQLInfo{
+				SQL:  "SELECT `s`.`id`, ROW_NUMBER() OVER (PARTITION BY `s`.`dept_id` ORDER BY `s`.`salary` DESC) AS `rn` FROM `test_salary` AS `s`;",
+				Args: []any{},
+			},
+		},
+		{
+			name:    "test invalid partition field",
+			s:       NewSelectSQL[TestSalary](db, nil).Fields(RowNumber().Over(PartitionBy("Invalid"))),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+		{
+			name:    "test invalid lag field",
+			s:       NewSelectSQL[TestSalary](db, nil).Fields(Lag("Invalid", 1).Over()),
+			wantErr: errs.NewErrNotSupportUnknownField("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestWindow_QueryWithContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	db := memoryDBWithDB("window", t)
+	_, err := db.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS `test_salary` (`id` INTEGER PRIMARY KEY, `dept_id` INTEGER, `salary` INTEGER);")
+	assert.NoError(t, err)
+	_, err = db.db.ExecContext(ctx, "INSERT INTO `test_salary` VALUES (1, 1, 100), (2, 1, 300), (3, 1, 200), (4, 2, 500), (5, 2, 400);")
+	assert.NoError(t, err)
+
+	res, err := NewSelectSQL[TestSalary](db, valuer.NewReflectValuer).
+		Fields(F("Id"), F("DeptId"), F("Salary"),
+			RowNumber().Over(PartitionBy("DeptId"), OrderBy(Desc("Salary"))).As("rn"),
+			Lag("Salary", 1).Over(PartitionBy("DeptId"), OrderBy(Desc("Salary"))).As("prev"),
+			Sum("Salary").Over(PartitionBy("DeptId")).As("total")).
+		OrderBy(Asc("DeptId"), Asc("Rn")).
+		QueryWithContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestSalary{
+		{Id: 2, DeptId: 1, Salary: 300, Rn: 1, Prev: sql.NullInt64{}, Total: 600},
+		{Id: 3, DeptId: 1, Salary: 200, Rn: 2, Prev: sql.NullInt64{Int64: 300, Valid: true}, Total: 600},
+		{Id: 1, DeptId: 1, Salary: 100, Rn: 3, Prev: sql.NullInt64{Int64: 200, Valid: true}, Total: 600},
+		{Id: 4, DeptId: 2, Salary: 500, Rn: 1, Prev: sql.NullInt64{}, Total: 900},
+		{Id: 5, DeptId: 2, Salary: 400, Rn: 2, Prev: sql.NullInt64{Int64: 500, Valid: true}, Total: 900},
+	}, res)
+}